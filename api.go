@@ -1,53 +1,161 @@
 package main
 
 import (
-	"encoding/json"
+	"bufio"
+	"context"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
+	"net/url"
 	"os"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
 	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"gitact/pkg/github"
 )
 
-func fetchGitHubActivity(username string) ([]GitHubEvent, error) {
-	url := fmt.Sprintf("https://api.github.com/users/%s/events", username)
+// proxyURL, when set by --proxy or GITACT_PROXY, replaces the proxy
+// taken from HTTP_PROXY/HTTPS_PROXY
+var proxyURL *url.URL
 
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("error creating the request: %v", err)
+// setProxy parses and installs a custom proxy for all API requests.
+// A bare "host:port" is treated as an http proxy.
+func setProxy(raw string) error {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil
+	}
+	if !strings.Contains(raw, "://") {
+		raw = "http://" + raw
 	}
 
-	req.Header.Set("User-Agent", "gh-act-cli/1.0")
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return fmt.Errorf("invalid proxy URL %q", raw)
+	}
+	proxyURL = u
+	return nil
+}
 
-	// Add GitHub token if available
-	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
-		req.Header.Set("Authorization", "token "+token)
+// proxyForRequest picks the proxy for a request. Without a custom proxy the
+// standard HTTP_PROXY/HTTPS_PROXY/NO_PROXY variables apply; with one,
+// hosts listed in NO_PROXY still go direct.
+func proxyForRequest(req *http.Request) (*url.URL, error) {
+	if proxyURL == nil {
+		return http.ProxyFromEnvironment(req)
+	}
+	if bypassProxy(req.URL.Hostname()) {
+		return nil, nil
 	}
+	return proxyURL, nil
+}
 
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("request http error: %v", err)
+// bypassProxy reports whether host matches an entry of NO_PROXY
+// ("*", an exact host, or a domain suffix such as ".github.com").
+func bypassProxy(host string) bool {
+	noProxy := os.Getenv("NO_PROXY")
+	if noProxy == "" {
+		noProxy = os.Getenv("no_proxy")
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode == 404 {
-		return nil, fmt.Errorf("user '%s' not found", username)
-	} else if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("http error %d", err)
+	host = strings.ToLower(host)
+	for _, entry := range strings.Split(noProxy, ",") {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+		if entry == "*" {
+			return true
+		}
+		if h, _, err := net.SplitHostPort(entry); err == nil {
+			entry = h
+		}
+		entry = strings.TrimPrefix(strings.TrimPrefix(entry, "*"), ".")
+		if host == entry || strings.HasSuffix(host, "."+entry) {
+			return true
+		}
 	}
+	return false
+}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("erreur lecture réponse: %v", err)
+// newHTTPClient returns a client whose transport routes requests through
+// proxyForRequest. The transport is set explicitly so proxy support does
+// not depend on http.Client falling back to the default transport.
+func newHTTPClient(timeout time.Duration) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = proxyForRequest
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
+
+// newClient returns the API client used by every mode, authenticated with
+// GITHUB_TOKEN, or the gh CLI token as a last resort, and going through the
+// configured proxy
+func newClient() *github.Client {
+	token := sanitizeToken(os.Getenv("GITHUB_TOKEN"))
+	if token == "" {
+		token = sanitizeToken(ghCLIToken())
+	}
+	client := github.NewClient(token)
+	client.HTTPClient = newHTTPClient(10 * time.Second)
+	// GITACT_API_URL points the whole load flow at another server, such as
+	// a GitHub Enterprise instance or a local stub serving canned JSON
+	if base := os.Getenv("GITACT_API_URL"); base != "" {
+		client.BaseURL = strings.TrimRight(base, "/")
+	}
+	return client
+}
+
+// sanitizeToken undoes the usual copy-paste accidents around a token:
+// surrounding whitespace or quotes, and a "Bearer " or "token " prefix
+// copied from an Authorization header
+func sanitizeToken(raw string) string {
+	token := strings.Trim(strings.TrimSpace(raw), `"'`)
+	for _, prefix := range []string{"bearer ", "token "} {
+		if len(token) > len(prefix) && strings.EqualFold(token[:len(prefix)], prefix) {
+			token = strings.TrimSpace(token[len(prefix):])
+			break
+		}
 	}
+	return token
+}
 
-	var events []GitHubEvent
-	if err := json.Unmarshal(body, &events); err != nil {
-		return nil, fmt.Errorf("erreur parsing JSON: %v", err)
+// mergeEvents appends to events the ones of more it doesn't already have.
+// Pages shift while new events arrive, so the start of a page can repeat
+// the end of the previous one.
+func mergeEvents(events, more []GitHubEvent) []GitHubEvent {
+	seen := make(map[string]bool, len(events))
+	for _, event := range events {
+		seen[event.ID] = true
 	}
+	merged := append([]GitHubEvent(nil), events...)
+	for _, event := range more {
+		if event.ID != "" && seen[event.ID] {
+			continue
+		}
+		seen[event.ID] = true
+		merged = append(merged, event)
+	}
+	return merged
+}
 
-	return events, nil
+// limitEvents keeps the n most recent events, newest first.
+// n <= 0 means no limit.
+func limitEvents(events []GitHubEvent, n int) []GitHubEvent {
+	if n <= 0 || len(events) <= n {
+		return events
+	}
+	sorted := append([]GitHubEvent(nil), events...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].CreatedAt.After(sorted[j].CreatedAt)
+	})
+	return sorted[:n]
 }
 
 func calculateStats(events []GitHubEvent) GitHubStats {
@@ -109,162 +217,349 @@ func getTopRepos(events []GitHubEvent) []RepoInfo {
 		repos = append(repos, repo)
 	}
 
-	// bull sorting
-	for i := 0; i < len(repos)-1; i++ {
-		for j := 0; j < len(repos)-i-1; j++ {
-			if repos[j].Count < repos[j+1].Count {
-				repos[j], repos[j+1] = repos[j+1], repos[j]
-			}
+	// most events first, ties broken by the latest activity then the name
+	// so the order doesn't depend on map iteration
+	sort.Slice(repos, func(i, j int) bool {
+		if repos[i].Count != repos[j].Count {
+			return repos[i].Count > repos[j].Count
 		}
-	}
+		if !repos[i].LastActivity.Equal(repos[j].LastActivity) {
+			return repos[i].LastActivity.After(repos[j].LastActivity)
+		}
+		return repos[i].Name < repos[j].Name
+	})
 
 	return repos
 }
 
-func printTopRepo(repos []RepoInfo) {
-	fmt.Printf("\n=== Top Repositories by Activity (%d total) ===\n", len(repos))
-	for i, repo := range repos {
-		fmt.Printf("\n%d. %s\n", i+1, repo.Name)
-		fmt.Printf("   Activity Events: %d\n", repo.Count)
-		fmt.Printf("   URL: %s\n", repo.URL)
-		fmt.Printf("   Last Activity: %s\n", repo.LastActivity.Format("2006-01-02 15:04:05"))
-		if repo.Description != "" {
-			fmt.Printf("   Description: %s\n", repo.Description)
+// contributedRepos returns the repos that username pushed to or opened
+// pull requests on without owning them, most events first. A repo counts
+// as owned when it is under username or in owned, which also covers
+// renamed accounts and repos beyond the listed pages.
+func contributedRepos(events []GitHubEvent, username string, owned []PublicRepo) []RepoInfo {
+	ownedNames := make(map[string]bool, len(owned))
+	for _, repo := range owned {
+		ownedNames[strings.ToLower(repo.FullName)] = true
+	}
+
+	var contributions []GitHubEvent
+	for _, event := range events {
+		if event.Type != "PushEvent" && event.Type != "PullRequestEvent" {
+			continue
+		}
+		name := strings.ToLower(event.Repo.Name)
+		owner, _, _ := strings.Cut(name, "/")
+		if name == "" || owner == strings.ToLower(username) || ownedNames[name] {
+			continue
 		}
+		contributions = append(contributions, event)
 	}
+	return getTopRepos(contributions)
 }
 
-func fetchPublicRepos(username string) ([]PublicRepo, error) {
-	var allRepos []PublicRepo
-	page := 1
-	perPage := 100
-
-	for {
-		url := fmt.Sprintf("https://api.github.com/users/%s/repos?type=public&sort=stars&direction=desc&per_page=%d&page=%d",
-			username, perPage, page)
+// eventTypeNames maps the names of --event-types to the type of the
+// events they keep
+var eventTypeNames = map[string]string{
+	"push":    "PushEvent",
+	"pr":      "PullRequestEvent",
+	"review":  "PullRequestReviewEvent",
+	"issues":  "IssuesEvent",
+	"comment": "IssueCommentEvent",
+	"create":  "CreateEvent",
+	"delete":  "DeleteEvent",
+	"release": "ReleaseEvent",
+	"fork":    "ForkEvent",
+	"star":    "WatchEvent",
+}
 
-		req, err := http.NewRequest("GET", url, nil)
-		if err != nil {
-			return nil, fmt.Errorf("error creating the request: %v", err)
+// eventTypeOrder lists the names of eventTypeNames in the order of the
+// help and of the types key
+var eventTypeOrder = []string{"push", "pr", "review", "issues", "comment", "create", "delete", "release", "fork", "star"}
+
+// parseEventTypes reads a comma-separated list of eventTypeNames, e.g.
+// "push,pr". An empty list gives nil, which keeps every event.
+func parseEventTypes(raw string) ([]string, error) {
+	var names []string
+	for _, name := range strings.Split(raw, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || slices.Contains(names, name) {
+			continue
 		}
-
-		req.Header.Set("User-Agent", "gh-act-cli/1.0")
-		req.Header.Set("Accept", "application/vnd.github.v3+json")
-
-		// Add GitHub token if available
-		if token := os.Getenv("GITHUB_TOKEN"); token != "" {
-			req.Header.Set("Authorization", "token "+token)
+		if _, ok := eventTypeNames[name]; !ok {
+			return nil, fmt.Errorf("unknown event type %q (valid types: %s)", name, strings.Join(eventTypeOrder, ", "))
 		}
+		names = append(names, name)
+	}
+	return names, nil
+}
 
-		client := &http.Client{Timeout: 10 * time.Second}
-		resp, err := client.Do(req)
-		if err != nil {
-			return nil, fmt.Errorf("request http error: %v", err)
+// filterEventTypes keeps the events of the given eventTypeNames, all of
+// them when names is empty
+func filterEventTypes(events []GitHubEvent, names []string) []GitHubEvent {
+	if len(names) == 0 {
+		return events
+	}
+	keep := make(map[string]bool, len(names))
+	for _, name := range names {
+		keep[eventTypeNames[name]] = true
+	}
+	var kept []GitHubEvent
+	for _, event := range events {
+		if keep[event.Type] {
+			kept = append(kept, event)
 		}
-		defer resp.Body.Close()
+	}
+	return kept
+}
 
-		if resp.StatusCode == 404 {
-			return nil, fmt.Errorf("user '%s' not found", username)
-		} else if resp.StatusCode != 200 {
-			return nil, fmt.Errorf("http error %d", resp.StatusCode)
-		}
+// feedEvents returns the events the activity feed shows: the most recent
+// ones up to --limit-activity, then those of --event-types. --limit-stats
+// computes the stats on them.
+func feedEvents(events []GitHubEvent, cfg Config) []GitHubEvent {
+	// an invalid list was reported at startup and keeps every event
+	names, _ := parseEventTypes(cfg.EventTypes)
+	return filterEventTypes(limitEvents(events, cfg.ActivityLimit), names)
+}
 
-		body, err := io.ReadAll(resp.Body)
-		if err != nil {
-			return nil, fmt.Errorf("error reading response: %v", err)
+// collapseWindow is the longest gap between two pushes merged by --collapse
+const collapseWindow = 10 * time.Minute
+
+// collapseEvents merges consecutive PushEvents of the same actor to the
+// same repo into one item when each follows the previous one within
+// window. The feed is newest first, so each item keeps its newest push.
+func collapseEvents(events []GitHubEvent, window time.Duration) []collapsedEvent {
+	var collapsed []collapsedEvent
+	for i, event := range events {
+		if n := len(collapsed); n > 0 && event.Type == "PushEvent" {
+			prev := events[i-1]
+			last := &collapsed[n-1]
+			if last.event.Type == "PushEvent" &&
+				last.event.Repo.Name == event.Repo.Name &&
+				last.event.Actor.Login == event.Actor.Login &&
+				prev.CreatedAt.Sub(event.CreatedAt).Abs() <= window {
+				last.count++
+				continue
+			}
 		}
+		collapsed = append(collapsed, collapsedEvent{event: event, count: 1})
+	}
+	return collapsed
+}
 
-		var repos []PublicRepo
-		if err := json.Unmarshal(body, &repos); err != nil {
-			return nil, fmt.Errorf("error parsing JSON: %v", err)
+// recentReleases returns the ReleaseEvents of a feed, most recent first
+func recentReleases(events []GitHubEvent) []GitHubEvent {
+	var releases []GitHubEvent
+	for _, event := range events {
+		if event.Type == "ReleaseEvent" && event.Payload.Release != nil {
+			releases = append(releases, event)
 		}
+	}
+	sort.SliceStable(releases, func(i, j int) bool {
+		return releases[i].CreatedAt.After(releases[j].CreatedAt)
+	})
+	return releases
+}
 
-		// Si aucun repo n'est retourné, on a atteint la fin
-		if len(repos) == 0 {
-			break
-		}
+// recentlyStarred groups the WatchEvents of a feed by target repo, most recent first.
+// WatchEvents in /users/<user>/events are stars the user gave, not stars
+// their repos received, so this lists what the user has been starring.
+func recentlyStarred(events []GitHubEvent) []RepoInfo {
+	starred := make(map[string]*RepoInfo)
+	var repos []*RepoInfo
 
-		// Filter only public repositories and add to collection
-		for _, repo := range repos {
-			if !repo.Private {
-				allRepos = append(allRepos, repo)
+	for _, event := range events {
+		if event.Type != "WatchEvent" || event.Repo.Name == "" {
+			continue
+		}
+		repo, ok := starred[event.Repo.Name]
+		if !ok {
+			repo = &RepoInfo{
+				Name:     event.Repo.Name,
+				URL:      fmt.Sprintf("https://github.com/%s", event.Repo.Name),
+				CloneURL: fmt.Sprintf("https://github.com/%s.git", event.Repo.Name),
 			}
+			starred[event.Repo.Name] = repo
+			repos = append(repos, repo)
 		}
-
-		// Si moins de repos que demandé, c'est la dernière page
-		if len(repos) < perPage {
-			break
+		repo.Count++
+		if event.CreatedAt.After(repo.LastActivity) {
+			repo.LastActivity = event.CreatedAt
 		}
+	}
 
-		page++
+	result := make([]RepoInfo, len(repos))
+	for i, repo := range repos {
+		result[i] = *repo
 	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].LastActivity.After(result[j].LastActivity)
+	})
+	return result
+}
 
-	return allRepos, nil
+// activityByWeekday counts events per day of the week (Sunday first),
+// using the day they happened on in loc
+func activityByWeekday(events []GitHubEvent, loc *time.Location) [7]int {
+	var days [7]int
+	for _, event := range events {
+		days[event.CreatedAt.In(loc).Weekday()]++
+	}
+	return days
 }
 
-// checkRateLimit checks GitHub API rate limit
-func checkRateLimit() error {
-	url := "https://api.github.com/rate_limit"
+func printTopRepo(repos []RepoInfo) {
+	fmt.Printf("\n=== Top Repositories by Activity (%d total) ===\n", len(repos))
+	for i, repo := range repos {
+		fmt.Printf("\n%d. %s\n", i+1, repo.Name)
+		fmt.Printf("   Activity Events: %d\n", repo.Count)
+		fmt.Printf("   URL: %s\n", repo.URL)
+		fmt.Printf("   Last Activity: %s\n", formatDateTime(repo.LastActivity))
+		if repo.Description != "" {
+			fmt.Printf("   Description: %s\n", sanitizeDisplay(repo.Description))
+		}
+	}
+}
 
-	req, err := http.NewRequest("GET", url, nil)
+// checkRateLimit checks GitHub API rate limit. The error is a warning,
+// the rate is still returned when it is low.
+func checkRateLimit(client *github.Client) (github.Rate, error) {
+	rate, err := client.RateLimit(context.Background())
 	if err != nil {
-		return fmt.Errorf("error creating rate limit request: %v", err)
+		return rate, fmt.Errorf("error checking rate limit: %w", err)
 	}
 
-	req.Header.Set("User-Agent", "gh-act-cli/1.0")
+	if rate.Remaining == 0 {
+		return rate, fmt.Errorf("rate limit exhausted: 0/%d remaining", rate.Limit)
+	}
+	if rate.Remaining < 10 {
+		return rate, fmt.Errorf("rate limit almost exhausted: %d/%d remaining, resets at %v",
+			rate.Remaining, rate.Limit, rate.Reset.Format("15:04:05"))
+	}
+
+	fmt.Printf("GitHub API Rate Limit: %d/%d requests remaining\n", rate.Remaining, rate.Limit)
+	return rate, nil
+}
 
-	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
-		req.Header.Set("Authorization", "token "+token)
+// waitForRateLimit runs when no request is left before the dashboard
+// starts, which would only show errors. It asks whether to quit or wait
+// for the reset, counting down, and reports whether to go on.
+func waitForRateLimit(rate github.Rate, in io.Reader) bool {
+	fmt.Printf("No API requests left until %s. Wait for the reset? [y/N] ", rate.Reset.Format("15:04:05"))
+	answer, _ := bufio.NewReader(in).ReadString('\n')
+	if a := strings.ToLower(strings.TrimSpace(answer)); a != "y" && a != "yes" {
+		return false
 	}
 
-	client := &http.Client{Timeout: 5 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		return fmt.Errorf("error checking rate limit: %v", err)
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for left := time.Until(rate.Reset); left > 0; left = time.Until(rate.Reset) {
+		fmt.Printf("\rRate limit resets in %s (ctrl+c to quit)   ", left.Round(time.Second))
+		<-ticker.C
 	}
-	defer resp.Body.Close()
+	fmt.Println()
+	return true
+}
+
+func printOrgs(username string, orgs []Org) {
+	fmt.Printf("\n=== Organizations of %s (%d) ===\n", username, len(orgs))
 
-	if resp.StatusCode != 200 {
-		return fmt.Errorf("rate limit check failed with status: %d", resp.StatusCode)
+	if len(orgs) == 0 {
+		fmt.Println("No public organization memberships.")
+		return
 	}
 
-	var rateLimit struct {
-		Resources struct {
-			Core struct {
-				Limit     int `json:"limit"`
-				Remaining int `json:"remaining"`
-				Reset     int `json:"reset"`
-			} `json:"core"`
-		} `json:"resources"`
+	for i, org := range orgs {
+		fmt.Printf("\n%d. %s\n", i+1, org.Login)
+		if org.Description != "" {
+			fmt.Printf("   Description: %s\n", sanitizeDisplay(org.Description))
+		}
+		fmt.Printf("   URL: %s\n", org.WebURL())
 	}
+}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("error reading rate limit response: %v", err)
+// repoFilter holds the --repos filters, the zero value keeps everything
+type repoFilter struct {
+	minStars     int       // --min-stars
+	updatedSince time.Time // --updated-since
+	hasFile      string    // --has-file, checked by filterHasFile
+}
+
+func (f repoFilter) active() bool {
+	return f.minStars > 0 || !f.updatedSince.IsZero() || f.hasFile != ""
+}
+
+// apply keeps the repositories passing every filter and returns how many
+// were left out
+func (f repoFilter) apply(repos []PublicRepo) ([]PublicRepo, int) {
+	if !f.active() {
+		return repos, 0
+	}
+	var kept []PublicRepo
+	for _, repo := range repos {
+		if repo.Stars >= f.minStars && !repo.UpdatedAt.Before(f.updatedSince) {
+			kept = append(kept, repo)
+		}
 	}
+	return kept, len(repos) - len(kept)
+}
+
+// hasFileWorkers bounds the concurrent requests of --has-file
+const hasFileWorkers = 8
 
-	if err := json.Unmarshal(body, &rateLimit); err != nil {
-		return fmt.Errorf("error parsing rate limit response: %v", err)
+// filterHasFile keeps the repos where path exists, one request each. A
+// warning is printed when the rate limit left can't cover the requests.
+func filterHasFile(client *github.Client, repos []PublicRepo, path string) ([]PublicRepo, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if rate, err := client.RateLimit(ctx); err == nil && rate.Remaining < len(repos) {
+		fmt.Fprintf(os.Stderr, "warning: --has-file needs %d requests but only %d are left until %s\n",
+			len(repos), rate.Remaining, rate.Reset.Local().Format("15:04"))
 	}
 
-	remaining := rateLimit.Resources.Core.Remaining
-	limit := rateLimit.Resources.Core.Limit
+	found := make([]bool, len(repos))
+	errs := make([]error, len(repos))
+	sem := make(chan struct{}, hasFileWorkers)
+	var wg sync.WaitGroup
+	for i, repo := range repos {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			found[i], errs[i] = client.HasFile(ctx, repo.FullName, path)
+			if errs[i] != nil {
+				cancel()
+			}
+		}()
+	}
+	wg.Wait()
 
-	if remaining < 10 {
-		resetTime := time.Unix(int64(rateLimit.Resources.Core.Reset), 0)
-		return fmt.Errorf("rate limit almost exhausted: %d/%d remaining, resets at %v",
-			remaining, limit, resetTime.Format("15:04:05"))
+	var kept []PublicRepo
+	for i, repo := range repos {
+		// the first failure cancels the others, report it rather than theirs
+		if errs[i] != nil && !errors.Is(errs[i], context.Canceled) {
+			return nil, fmt.Errorf("error checking %s in %s: %w", path, repo.FullName, errs[i])
+		}
+		if found[i] {
+			kept = append(kept, repo)
+		}
 	}
+	return kept, nil
+}
 
-	fmt.Printf("GitHub API Rate Limit: %d/%d requests remaining\n", remaining, limit)
-	return nil
+// printPublicRepos lists repos, hidden is the number left out by the filters
+func printPublicRepos(repos []PublicRepo, hidden int) {
+	writePublicRepos(os.Stdout, repos, hidden, terminalWidth())
 }
 
-func printPublicRepos(repos []PublicRepo) {
-	fmt.Printf("\n=== Public Repositories (%d total) ===\n", len(repos))
+// writePublicRepos writes the repo listing of printPublicRepos. Names and
+// descriptions are cut to width, 0 for no limit.
+func writePublicRepos(w io.Writer, repos []PublicRepo, hidden, width int) {
+	fmt.Fprintf(w, "\n=== Public Repositories (%d total) ===\n", len(repos))
 
 	if len(repos) == 0 {
-		fmt.Println("No public repositories found.")
+		fmt.Fprintln(w, "No public repositories found.")
 		return
 	}
 
@@ -278,18 +573,35 @@ func printPublicRepos(repos []PublicRepo) {
 	}
 
 	for i, repo := range repos {
-		fmt.Printf("\n%d. %s\n", i+1, repo.FullName)
-		fmt.Printf("   Stars: %d | 🍴 Forks: %d\n", repo.Stars, repo.Forks)
+		prefix := fmt.Sprintf("%d. ", i+1)
+		name := repo.FullName
+		if width > 0 {
+			name = truncate(name, width-len(prefix))
+		}
+		fmt.Fprintf(w, "\n%s%s\n", prefix, name)
+		fmt.Fprintf(w, "   Stars: %d | 🍴 Forks: %d\n", repo.Stars, repo.Forks)
 		if repo.Language != "" {
-			fmt.Printf("   Language: %s\n", repo.Language)
+			fmt.Fprintf(w, "   Language: %s\n", repo.Language)
 		}
-		if repo.Description != "" {
-			fmt.Printf("   Description: %s\n", repo.Description)
+		if desc := sanitizeDisplay(repo.Description); desc != "" {
+			if width > 0 {
+				desc = truncate(desc, width-len("   Description: "))
+			}
+			fmt.Fprintf(w, "   Description: %s\n", desc)
 		}
-		fmt.Printf("   URL: %s\n", repo.URL)
-		fmt.Printf("   Created: %s | Updated: %s\n",
-			repo.CreatedAt.Format("2006-01-02"),
-			repo.UpdatedAt.Format("2006-01-02"))
+		fmt.Fprintf(w, "   URL: %s\n", repo.URL)
+		if homepage := strings.TrimSpace(repo.Homepage); homepage != "" {
+			fmt.Fprintf(w, "   Homepage: %s\n", homepage)
+		}
+		label, color := repoHealth(repo)
+		fmt.Fprintf(w, "   Health: %s (%d open issues and PRs)\n",
+			lipgloss.NewStyle().Foreground(color).Render(label), repo.OpenIssues)
+		if repo.DefaultBranch != "" {
+			fmt.Fprintf(w, "   Default Branch: %s\n", repo.DefaultBranch)
+		}
+		fmt.Fprintf(w, "   Created: %s | Updated: %s\n",
+			formatDate(repo.CreatedAt),
+			formatDate(repo.UpdatedAt))
 	}
 
 	// Show summary at the end
@@ -297,7 +609,123 @@ func printPublicRepos(repos []PublicRepo) {
 	for _, repo := range repos {
 		totalStars += repo.Stars
 	}
-	fmt.Printf("\nSummary: %d repositories with %d total stars\n", len(repos), totalStars)
+	if hidden > 0 {
+		fmt.Fprintf(w, "\nSummary: %d repositories with %d total stars (%d filtered out)\n", len(repos), totalStars, hidden)
+	} else {
+		fmt.Fprintf(w, "\nSummary: %d repositories with %d total stars\n", len(repos), totalStars)
+	}
+}
+
+// sortedByCount returns the keys of counts by count (descending), then by
+// name, so printed output is stable between runs
+func sortedByCount(counts map[string]int) []string {
+	keys := make([]string, 0, len(counts))
+	for k := range counts {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if counts[keys[i]] != counts[keys[j]] {
+			return counts[keys[i]] > counts[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
+
+// topTopics returns the n topics most used across repos, the first by
+// name on a tie
+func topTopics(repos []PublicRepo, n int) []string {
+	counts := make(map[string]int)
+	for _, repo := range repos {
+		for _, topic := range repo.Topics {
+			counts[topic]++
+		}
+	}
+	topics := sortedByCount(counts)
+	return topics[:min(len(topics), n)]
+}
+
+// relatedPerTopic is the number of search results read for each topic
+const relatedPerTopic = 10
+
+// fetchRelatedRepos searches the most starred repos of each topic, leaving
+// out the ones owned by owner. Each topic is one search request.
+func fetchRelatedRepos(ctx context.Context, client *github.Client, topics []string, owner string) ([]PublicRepo, error) {
+	seen := make(map[string]bool)
+	var related []PublicRepo
+	for _, topic := range topics {
+		repos, err := client.SearchRepos(ctx, "topic:"+topic, relatedPerTopic)
+		if err != nil {
+			return related, err
+		}
+		for _, repo := range repos {
+			repoOwner, _, _ := strings.Cut(repo.FullName, "/")
+			if strings.EqualFold(repoOwner, owner) || seen[repo.FullName] {
+				continue
+			}
+			seen[repo.FullName] = true
+			related = append(related, repo)
+		}
+	}
+	sort.SliceStable(related, func(i, j int) bool { return related[i].Stars > related[j].Stars })
+	return related, nil
+}
+
+// reposByYear counts the repos created each year
+func reposByYear(repos []PublicRepo) map[int]int {
+	years := make(map[int]int)
+	for _, repo := range repos {
+		if !repo.CreatedAt.IsZero() {
+			years[repo.CreatedAt.Year()]++
+		}
+	}
+	return years
+}
+
+// dominantLanguage returns the most common language of repos, the first
+// by name on a tie, or "" when none has a language
+func dominantLanguage(repos []PublicRepo) string {
+	counts := make(map[string]int)
+	for _, repo := range repos {
+		if repo.Language != "" {
+			counts[repo.Language]++
+		}
+	}
+	if langs := sortedByCount(counts); len(langs) > 0 {
+		return langs[0]
+	}
+	return ""
+}
+
+// languageActiveMonths is how recently a repository must have been
+// updated for its language to count as active
+const languageActiveMonths = 12
+
+// languageActivity splits the languages of repos into the ones with a
+// repository updated in the last languageActiveMonths before now and the
+// dormant ones, each ordered by number of repositories
+func languageActivity(repos []PublicRepo, now time.Time) (active, dormant []string) {
+	cutoff := now.AddDate(0, -languageActiveMonths, 0)
+	counts := make(map[string]int)
+	recent := make(map[string]bool)
+	for _, repo := range repos {
+		if repo.Language == "" {
+			continue
+		}
+		counts[repo.Language]++
+		if repo.UpdatedAt.After(cutoff) {
+			recent[repo.Language] = true
+		}
+	}
+
+	for _, lang := range sortedByCount(counts) {
+		if recent[lang] {
+			active = append(active, lang)
+		} else {
+			dormant = append(dormant, lang)
+		}
+	}
+	return active, dormant
 }
 
 func calculatePublicReposStats(repos []PublicRepo) {
@@ -345,8 +773,8 @@ func calculatePublicReposStats(repos []PublicRepo) {
 
 	if len(languageCount) > 0 {
 		fmt.Printf("\nProgramming Languages Used:\n")
-		for lang, count := range languageCount {
-			fmt.Printf("   - %s: %d repositories\n", lang, count)
+		for _, lang := range sortedByCount(languageCount) {
+			fmt.Printf("   - %s: %d repositories\n", lang, languageCount[lang])
 		}
 	}
 }
@@ -0,0 +1,286 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// event builds a feed entry of type on repo at t
+func event(typ, repo string, t time.Time) GitHubEvent {
+	return GitHubEvent{Type: typ, Repo: Repo{Name: repo}, CreatedAt: t}
+}
+
+func TestRecentlyStarred(t *testing.T) {
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	events := []GitHubEvent{
+		event("WatchEvent", "a/one", base),
+		event("PushEvent", "a/two", base.Add(time.Hour)),
+		event("WatchEvent", "b/two", base.Add(2*time.Hour)),
+		event("WatchEvent", "a/one", base.Add(3*time.Hour)),
+		event("WatchEvent", "", base.Add(4*time.Hour)),
+	}
+
+	got := recentlyStarred(events)
+	if len(got) != 2 {
+		t.Fatalf("got %d repos, want 2: %+v", len(got), got)
+	}
+	// most recently starred first, repeated stars counted on the same repo
+	if got[0].Name != "a/one" || got[0].Count != 2 || !got[0].LastActivity.Equal(base.Add(3*time.Hour)) {
+		t.Errorf("first = %+v, want a/one starred twice, last at +3h", got[0])
+	}
+	if got[1].Name != "b/two" || got[1].Count != 1 {
+		t.Errorf("second = %+v, want b/two starred once", got[1])
+	}
+	if got[0].URL != "https://github.com/a/one" {
+		t.Errorf("URL = %q", got[0].URL)
+	}
+}
+
+func TestRecentlyStarredNoStars(t *testing.T) {
+	events := []GitHubEvent{event("PushEvent", "a/one", time.Now())}
+	if got := recentlyStarred(events); len(got) != 0 {
+		t.Errorf("got %+v, want nothing", got)
+	}
+}
+
+func TestSortedByCount(t *testing.T) {
+	counts := map[string]int{"Rust": 2, "Go": 5, "C": 2, "Zig": 1, "Ada": 2}
+	want := []string{"Go", "Ada", "C", "Rust", "Zig"}
+
+	// map iteration order changes between runs, the result must not
+	for i := 0; i < 20; i++ {
+		got := sortedByCount(counts)
+		if !slices.Equal(got, want) {
+			t.Fatalf("run %d: got %v, want %v", i, got, want)
+		}
+	}
+}
+
+func TestActivityByWeekday(t *testing.T) {
+	// 2024-05-05 is a Sunday
+	sunday := time.Date(2024, 5, 5, 12, 0, 0, 0, time.UTC)
+	events := []GitHubEvent{
+		event("PushEvent", "a/one", sunday),
+		event("PushEvent", "a/one", sunday.AddDate(0, 0, 2)), // Tuesday
+		event("PushEvent", "a/one", sunday.AddDate(0, 0, 2)),
+		event("PushEvent", "a/one", sunday.AddDate(0, 0, 9)), // Tuesday
+		event("PushEvent", "a/one", sunday.AddDate(0, 0, 6)), // Saturday
+	}
+
+	got := activityByWeekday(events, time.UTC)
+	want := [7]int{1, 0, 3, 0, 0, 0, 1}
+	if got != want {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestActivityByWeekdayTimezone(t *testing.T) {
+	// late Sunday evening in UTC is already Monday in Tokyo
+	tokyo := time.FixedZone("JST", 9*60*60)
+	events := []GitHubEvent{event("PushEvent", "a/one", time.Date(2024, 5, 5, 22, 0, 0, 0, time.UTC))}
+
+	if got := activityByWeekday(events, time.UTC); got[time.Sunday] != 1 {
+		t.Errorf("UTC: got %v, want the event on Sunday", got)
+	}
+	if got := activityByWeekday(events, tokyo); got[time.Monday] != 1 {
+		t.Errorf("JST: got %v, want the event on Monday", got)
+	}
+}
+
+func TestSanitizeToken(t *testing.T) {
+	tests := []struct {
+		raw, want string
+	}{
+		{"ghp_abc123", "ghp_abc123"},
+		{"  ghp_abc123\n", "ghp_abc123"},
+		{`"ghp_abc123"`, "ghp_abc123"},
+		{"'ghp_abc123'", "ghp_abc123"},
+		{"Bearer ghp_abc123", "ghp_abc123"},
+		{"bearer  ghp_abc123", "ghp_abc123"},
+		{"token ghp_abc123", "ghp_abc123"},
+		{"TOKEN ghp_abc123", "ghp_abc123"},
+		{` "Bearer ghp_abc123" `, "ghp_abc123"},
+		// only one prefix is removed, and a bare prefix is left alone
+		{"token token", "token"},
+		{"Bearer ", "Bearer"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := sanitizeToken(tt.raw); got != tt.want {
+			t.Errorf("sanitizeToken(%q) = %q, want %q", tt.raw, got, tt.want)
+		}
+	}
+}
+
+func TestCollapseEvents(t *testing.T) {
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	push := func(repo, actor string, ago time.Duration) GitHubEvent {
+		ev := event("PushEvent", repo, base.Add(-ago))
+		ev.Actor.Login = actor
+		return ev
+	}
+
+	// newest first, as the feed comes
+	events := []GitHubEvent{
+		push("a/one", "me", 0),
+		push("a/one", "me", 10*time.Minute),
+		push("a/one", "me", 20*time.Minute),
+		push("a/one", "other", 25*time.Minute), // another actor breaks the run
+		push("a/one", "me", 30*time.Minute),
+		event("WatchEvent", "b/two", base.Add(-40*time.Minute)), // so does another type
+		push("a/one", "me", 50*time.Minute),
+		push("a/two", "me", 55*time.Minute),                  // and another repo
+		push("a/two", "me", 5*time.Hour),                     // too long after the previous push
+		event("WatchEvent", "b/two", base.Add(-6*time.Hour)), // only pushes collapse
+		event("WatchEvent", "b/two", base.Add(-6*time.Hour)),
+	}
+
+	got := collapseEvents(events, time.Hour)
+	want := []struct {
+		repo  string
+		count int
+		at    time.Time
+	}{
+		{"a/one", 3, base},
+		{"a/one", 1, base.Add(-25 * time.Minute)},
+		{"a/one", 1, base.Add(-30 * time.Minute)},
+		{"b/two", 1, base.Add(-40 * time.Minute)},
+		{"a/one", 1, base.Add(-50 * time.Minute)},
+		{"a/two", 1, base.Add(-55 * time.Minute)},
+		{"a/two", 1, base.Add(-5 * time.Hour)},
+		{"b/two", 1, base.Add(-6 * time.Hour)},
+		{"b/two", 1, base.Add(-6 * time.Hour)},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("%d items, want %d: %+v", len(got), len(want), got)
+	}
+	for i, w := range want {
+		g := got[i]
+		if g.event.Repo.Name != w.repo || g.count != w.count || !g.event.CreatedAt.Equal(w.at) {
+			t.Errorf("item %d = %s x%d at %v, want %s x%d at %v",
+				i, g.event.Repo.Name, g.count, g.event.CreatedAt, w.repo, w.count, w.at)
+		}
+	}
+}
+
+func TestCollapseEventsChain(t *testing.T) {
+	// each push is compared with the one before it, not the first of the
+	// run, so a steady stream collapses even past the window in total
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	var events []GitHubEvent
+	for i := 0; i < 5; i++ {
+		events = append(events, event("PushEvent", "a/one", base.Add(-time.Duration(i)*45*time.Minute)))
+	}
+	if got := collapseEvents(events, time.Hour); len(got) != 1 || got[0].count != 5 {
+		t.Errorf("got %+v, want one item of 5 pushes", got)
+	}
+}
+
+func TestReposByYear(t *testing.T) {
+	created := func(year int) PublicRepo {
+		return PublicRepo{CreatedAt: time.Date(year, 3, 1, 0, 0, 0, 0, time.UTC)}
+	}
+	repos := []PublicRepo{created(2019), created(2021), created(2021), created(2024), {}}
+
+	got := reposByYear(repos)
+	want := map[int]int{2019: 1, 2021: 2, 2024: 1}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for year, n := range want {
+		if got[year] != n {
+			t.Errorf("%d: got %d, want %d", year, got[year], n)
+		}
+	}
+}
+
+func TestParseEventTypes(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want []string
+	}{
+		{"", nil},
+		{"push", []string{"push"}},
+		{"push,pr", []string{"push", "pr"}},
+		{" Push , PR ,, push ", []string{"push", "pr"}},
+		{"star,fork,release", []string{"star", "fork", "release"}},
+	}
+	for _, tt := range tests {
+		got, err := parseEventTypes(tt.raw)
+		if err != nil || !slices.Equal(got, tt.want) {
+			t.Errorf("parseEventTypes(%q) = %v, %v, want %v", tt.raw, got, err, tt.want)
+		}
+	}
+
+	for _, raw := range []string{"pushes", "push,watch", "PushEvent"} {
+		if got, err := parseEventTypes(raw); err == nil {
+			t.Errorf("parseEventTypes(%q) = %v, want an error", raw, got)
+		}
+	}
+}
+
+func TestEventTypeNamesInOrder(t *testing.T) {
+	// the t key cycles through eventTypeOrder, every name must be in it once
+	if len(eventTypeOrder) != len(eventTypeNames) {
+		t.Fatalf("%d names in the order, %d types", len(eventTypeOrder), len(eventTypeNames))
+	}
+	for _, name := range eventTypeOrder {
+		if _, ok := eventTypeNames[name]; !ok {
+			t.Errorf("%q is in eventTypeOrder but not eventTypeNames", name)
+		}
+	}
+}
+
+func TestFilterEventTypes(t *testing.T) {
+	now := time.Now()
+	events := []GitHubEvent{
+		event("PushEvent", "a/one", now),
+		event("WatchEvent", "a/one", now),
+		event("PullRequestEvent", "a/one", now),
+		event("GollumEvent", "a/one", now),
+		event("PushEvent", "a/two", now),
+	}
+
+	tests := []struct {
+		names []string
+		want  []string
+	}{
+		{nil, []string{"PushEvent", "WatchEvent", "PullRequestEvent", "GollumEvent", "PushEvent"}},
+		{[]string{"push"}, []string{"PushEvent", "PushEvent"}},
+		{[]string{"star", "pr"}, []string{"WatchEvent", "PullRequestEvent"}},
+		{[]string{"release"}, nil},
+	}
+	for _, tt := range tests {
+		var got []string
+		for _, event := range filterEventTypes(events, tt.names) {
+			got = append(got, event.Type)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("filterEventTypes(%v) = %v, want %v", tt.names, got, tt.want)
+		}
+	}
+}
+
+func TestWritePublicReposDescription(t *testing.T) {
+	desc := strings.Repeat("a long description ", 8) + "END"
+	repos := []PublicRepo{{FullName: "octocat/cli", Description: desc}}
+
+	// redirected output keeps the description whole on one line
+	var b strings.Builder
+	writePublicRepos(&b, repos, 0, 0)
+	if !strings.Contains(b.String(), "   Description: "+desc+"\n") {
+		t.Errorf("description changed without a terminal:\n%s", b.String())
+	}
+
+	b.Reset()
+	writePublicRepos(&b, repos, 0, 60)
+	_, line, _ := strings.Cut(b.String(), "   Description: ")
+	line, _, _ = strings.Cut("   Description: "+line, "\n")
+	if w := lipgloss.Width(line); w != 60 || !strings.HasSuffix(line, "…") {
+		t.Errorf("description line %q is %d wide, want 60 ending with an ellipsis", line, w)
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"os"
+	"strings"
+
+	"gitact/pkg/github"
+)
+
+// badgeColors maps the activity grade to the right-hand badge color
+var badgeColors = map[string]string{
+	"S+": "#4c1", "S": "#4c1", "A+": "#97ca00", "A": "#97ca00",
+	"B+": "#a4a61d", "B": "#dfb317", "C": "#fe7d37", "D": "#e05d44", "F": "#9f9f9f",
+}
+
+// badgeTextWidth estimates the width in pixels of text in the 11px
+// Verdana used by shields.io badges
+func badgeTextWidth(text string) int {
+	return len([]rune(text))*7 + 10
+}
+
+// renderBadge builds a flat shields.io-style SVG with a label and a value
+func renderBadge(label, value, color string) string {
+	lw, vw := badgeTextWidth(label), badgeTextWidth(value)
+	label, value = html.EscapeString(label), html.EscapeString(value)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="20" role="img" aria-label="%s: %s">`, lw+vw, label, value)
+	fmt.Fprintf(&b, `<title>%s: %s</title>`, label, value)
+	fmt.Fprintf(&b, `<rect width="%d" height="20" rx="3" fill="#555"/>`, lw+vw)
+	fmt.Fprintf(&b, `<rect x="%d" width="%d" height="20" rx="3" fill="%s"/>`, lw, vw, color)
+	fmt.Fprintf(&b, `<rect x="%d" width="4" height="20" fill="%s"/>`, lw, color)
+	b.WriteString(`<g fill="#fff" text-anchor="middle" font-family="Verdana,Geneva,DejaVu Sans,sans-serif" font-size="11">`)
+	fmt.Fprintf(&b, `<text x="%d" y="14">%s</text>`, lw/2, label)
+	fmt.Fprintf(&b, `<text x="%d" y="14">%s</text>`, lw+vw/2, value)
+	b.WriteString("</g></svg>\n")
+	return b.String()
+}
+
+// writeBadge fetches the user's activity and repositories and writes a
+// badge with their activity grade and total stars
+func writeBadge(w io.Writer, client *github.Client, username string) error {
+	ctx := context.Background()
+
+	events, err := client.FetchActivity(ctx, username, true)
+	if err != nil {
+		return fmt.Errorf("error fetching activity: %w", err)
+	}
+	// a page-limited list still gives a meaningful star count
+	repos, err := client.FetchRepos(ctx, username)
+	if errors.Is(err, github.ErrPageLimit) {
+		warnPageLimit(client, username, len(repos))
+	} else if err != nil {
+		return fmt.Errorf("error fetching public repositories: %w", err)
+	}
+
+	grade := getGrade(calculateStats(events))
+	totalStars := 0
+	for _, repo := range repos {
+		totalStars += repo.Stars
+	}
+
+	value := fmt.Sprintf("grade %s | %s stars", grade, formatNumber(totalStars))
+	_, err = io.WriteString(w, renderBadge(username, value, badgeColors[grade]))
+	return err
+}
+
+// showBadge runs --badge, writing to output or stdout when empty.
+// The badge is built before the file is created, so a failed fetch
+// doesn't leave an empty file behind.
+func showBadge(client *github.Client, username, output string) {
+	var buf strings.Builder
+	if err := writeBadge(&buf, client, username); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(exitCode(err))
+	}
+
+	if output == "" {
+		fmt.Print(buf.String())
+		return
+	}
+	if err := os.WriteFile(output, []byte(buf.String()), 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "error writing %s: %v\n", output, err)
+		os.Exit(1)
+	}
+}
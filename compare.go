@@ -0,0 +1,242 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"gitact/pkg/github"
+)
+
+// compareMetrics lists the compared totals in display order
+var compareMetrics = []string{
+	"public_repos", "followers", "stars", "forks", "events", "push_events", "pull_requests",
+}
+
+// userTotals holds the compared totals of one user. Error is set instead
+// of Totals when the user couldn't be loaded, err keeps the cause for the
+// exit status.
+type userTotals struct {
+	Username string         `json:"username"`
+	Totals   map[string]int `json:"totals,omitempty"`
+	Error    string         `json:"error,omitempty"`
+	err      error
+}
+
+// comparison is the result of --compare. Winner maps each metric to the
+// user with the highest total, "tie" when equal, and is empty when fewer
+// than two users could be loaded.
+type comparison struct {
+	Users  []userTotals      `json:"users"`
+	Winner map[string]string `json:"winner"`
+}
+
+// fetchTotals loads the profile, repositories and activity of a user
+func fetchTotals(ctx context.Context, client *github.Client, username string) (map[string]int, error) {
+	profile, err := client.FetchProfile(ctx, username)
+	if err != nil {
+		return nil, err
+	}
+	// a page-limited list still gives meaningful totals
+	repos, err := client.FetchRepos(ctx, username)
+	if errors.Is(err, github.ErrPageLimit) {
+		warnPageLimit(client, username, len(repos))
+	} else if err != nil {
+		return nil, err
+	}
+	events, err := client.FetchActivity(ctx, username, true)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := calculateStats(events)
+	totals := map[string]int{
+		"public_repos":  profile.PublicRepos,
+		"followers":     profile.Followers,
+		"events":        stats.TotalEvents,
+		"push_events":   stats.PushEvents,
+		"pull_requests": stats.PullRequestEvents,
+	}
+	for _, repo := range repos {
+		totals["stars"] += repo.Stars
+		totals["forks"] += repo.Forks
+	}
+	return totals, nil
+}
+
+// compareUsers loads every user and picks a winner per metric among
+// the ones that loaded
+func compareUsers(ctx context.Context, client *github.Client, usernames []string) comparison {
+	result := comparison{Winner: make(map[string]string)}
+	var loaded []userTotals
+
+	for _, username := range usernames {
+		u := userTotals{Username: username}
+		totals, err := fetchTotals(ctx, client, username)
+		if err != nil {
+			u.Error = err.Error()
+			u.err = err
+		} else {
+			u.Totals = totals
+			loaded = append(loaded, u)
+		}
+		result.Users = append(result.Users, u)
+	}
+
+	if len(loaded) < 2 {
+		return result
+	}
+	for _, metric := range compareMetrics {
+		best, winner := -1, ""
+		for _, u := range loaded {
+			switch v := u.Totals[metric]; {
+			case v > best:
+				best, winner = v, u.Username
+			case v == best:
+				winner = "tie"
+			}
+		}
+		result.Winner[metric] = winner
+	}
+	return result
+}
+
+func writeComparisonJSON(w io.Writer, c comparison) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(c)
+}
+
+func printComparison(c comparison) {
+	fmt.Printf("\n=== Comparison ===\n")
+	for _, u := range c.Users {
+		if u.Error != "" {
+			fmt.Printf("\n%s: ❌ %s\n", u.Username, u.Error)
+			continue
+		}
+		fmt.Printf("\n%s\n", u.Username)
+		for _, metric := range compareMetrics {
+			mark := ""
+			if c.Winner[metric] == u.Username {
+				mark = " ★"
+			}
+			fmt.Printf("   %-14s %s%s\n", metric, formatNumber(u.Totals[metric]), mark)
+		}
+	}
+}
+
+// showComparison runs --compare, exiting with an error status only when
+// no user could be loaded, the one of the first user's error
+func showComparison(client *github.Client, usernames []string, jsonOutput bool) {
+	c := compareUsers(context.Background(), client, usernames)
+
+	if jsonOutput {
+		if err := writeComparisonJSON(os.Stdout, c); err != nil {
+			fmt.Fprintf(os.Stderr, "error writing output: %v\n", err)
+			os.Exit(1)
+		}
+	} else {
+		printComparison(c)
+	}
+
+	var err error
+	for _, u := range c.Users {
+		if u.err == nil {
+			return
+		}
+		if err == nil {
+			err = u.err
+		}
+	}
+	os.Exit(exitCode(err))
+}
+
+// formatAge gives a rough age such as "3y 2mo", "5mo" or "12d"
+func formatAge(d time.Duration) string {
+	days := int(d.Hours() / 24)
+	switch {
+	case days < 30:
+		return fmt.Sprintf("%dd", days)
+	case days < 365:
+		return fmt.Sprintf("%dmo", days/30)
+	default:
+		return fmt.Sprintf("%dy %dmo", days/365, days%365/30)
+	}
+}
+
+// repoComparisonRow is a line of renderRepoComparison. better is -1 when
+// the first repo wins, 1 for the second and 0 when nobody does.
+type repoComparisonRow struct {
+	label  string
+	a, b   string
+	better int
+}
+
+// higher tells which of two values is larger, in the way of
+// repoComparisonRow.better
+func higher(a, b float64) int {
+	switch {
+	case a > b:
+		return -1
+	case b > a:
+		return 1
+	}
+	return 0
+}
+
+// renderRepoComparison shows two repositories in columns, the better
+// value of a row starred
+func renderRepoComparison(a, b PublicRepo, now time.Time) string {
+	language := func(r PublicRepo) string {
+		if r.Language == "" {
+			return "-"
+		}
+		return r.Language
+	}
+	healthA, _ := repoHealthAt(a, now)
+	healthB, _ := repoHealthAt(b, now)
+	scoreA, scoreB := popularityScoreAt(a, now), popularityScoreAt(b, now)
+
+	rows := []repoComparisonRow{
+		{"Stars", formatNumber(a.Stars), formatNumber(b.Stars), higher(float64(a.Stars), float64(b.Stars))},
+		{"Forks", formatNumber(a.Forks), formatNumber(b.Forks), higher(float64(a.Forks), float64(b.Forks))},
+		{"Language", language(a), language(b), 0},
+		{"Age", formatAge(now.Sub(a.CreatedAt)), formatAge(now.Sub(b.CreatedAt)), 0},
+		{"Last update", formatDate(a.UpdatedAt), formatDate(b.UpdatedAt), higher(float64(a.UpdatedAt.Unix()), float64(b.UpdatedAt.Unix()))},
+		{"Open issues/PRs", formatNumber(a.OpenIssues), formatNumber(b.OpenIssues), 0},
+		{"Health", healthA, healthB, 0},
+		{"Popularity", fmt.Sprintf("%.1f", scoreA), fmt.Sprintf("%.1f", scoreB), higher(scoreA, scoreB)},
+	}
+
+	labelWidth := len("Open issues/PRs") + 2
+	width := max(lipgloss.Width(a.Name), lipgloss.Width(b.Name)) + 4
+	for _, row := range rows {
+		width = max(width, lipgloss.Width(row.a)+4, lipgloss.Width(row.b)+4)
+	}
+	cell := func(s string, w int) string {
+		return lipgloss.NewStyle().Width(w).Render(s)
+	}
+	star := func(s string, won bool) string {
+		if won {
+			return lipgloss.NewStyle().Foreground(nvimGreen).Render(s + " ★")
+		}
+		return s
+	}
+
+	var content strings.Builder
+	content.WriteString(cell("", labelWidth) + titleStyle.Render(cell(a.Name, width)) + titleStyle.Render(cell(b.Name, width)) + "\n")
+	for _, row := range rows {
+		content.WriteString(statLabelStyle.Render(cell(row.label, labelWidth)))
+		content.WriteString(cell(star(row.a, row.better < 0), width))
+		content.WriteString(cell(star(row.b, row.better > 0), width))
+		content.WriteString("\n")
+	}
+	return content.String()
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"gitact/pkg/github"
+)
+
+func TestCompareUsersKeepsErrors(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	t.Cleanup(srv.Close)
+	client := github.NewClient("")
+	client.BaseURL = srv.URL
+
+	c := compareUsers(context.Background(), client, []string{"ghost-one", "ghost-two"})
+	for _, u := range c.Users {
+		if u.Error == "" || !errors.Is(u.err, github.ErrUserNotFound) {
+			t.Errorf("%s: Error %q, err %v, want a user not found error", u.Username, u.Error, u.err)
+		}
+		// the exit status of a comparison where nobody loaded
+		if got := exitCode(u.err); got != exitUserNotFound {
+			t.Errorf("%s: exit status %d, want %d", u.Username, got, exitUserNotFound)
+		}
+	}
+	if len(c.Winner) != 0 {
+		t.Errorf("winners without users: %v", c.Winner)
+	}
+}
@@ -0,0 +1,253 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// Config holds the user settings read from the config file.
+// Command line flags override the matching fields.
+type Config struct {
+	// Keys maps an action name (e.g. "up", "clone") to the keys bound to it
+	Keys map[string][]string `json:"keys,omitempty"`
+
+	// PublicOnly restricts activity to the public events endpoint
+	PublicOnly bool `json:"public_only,omitempty"`
+
+	// Timezone is an IANA name (e.g. "Europe/Paris") for day-based stats,
+	// local time when empty
+	Timezone string `json:"timezone,omitempty"`
+
+	// ActivityLimit caps the activity feed to the most recent events,
+	// no cap when zero
+	ActivityLimit int `json:"activity_limit,omitempty"`
+
+	// LimitStats computes activity stats on the capped feed only
+	LimitStats bool `json:"limit_stats,omitempty"`
+
+	// DateFormat is a preset ("iso", "us", "relative") or a Go layout
+	// such as "02 Jan 2006"
+	DateFormat string `json:"date_format,omitempty"`
+
+	// NoRateCheck skips the rate limit request made before the dashboard starts
+	NoRateCheck bool `json:"no_rate_check,omitempty"`
+
+	// NotifDuration is how long notifications stay visible, e.g. "5s"
+	NotifDuration string `json:"notif_duration,omitempty"`
+
+	// NoEmoji leaves emoji out of notifications
+	NoEmoji bool `json:"no_emoji,omitempty"`
+
+	// NoMotion turns off the highlight of the view name after switching
+	NoMotion bool `json:"no_motion,omitempty"`
+
+	// Interval refreshes the dashboard periodically, e.g. "5m", never
+	// when empty
+	Interval string `json:"interval,omitempty"`
+
+	// Accent is a hex color (e.g. "#7aa2f7") replacing the accent of the
+	// header, titles and selection
+	Accent string `json:"accent,omitempty"`
+
+	// Collapse merges consecutive pushes to the same repo in the feed
+	Collapse bool `json:"collapse,omitempty"`
+
+	// NativeFilter uses the fuzzy filter of the lists on / instead of the
+	// search bar and its lang:, stars: and fork: syntax
+	NativeFilter bool `json:"native_filter,omitempty"`
+
+	// NoMouse starts the dashboard without mouse support, so the terminal
+	// can select text
+	NoMouse bool `json:"no_mouse,omitempty"`
+
+	// ListItems caps the items visible at once in the lists, the rest
+	// are paged. Zero fits the lists to the terminal.
+	ListItems int `json:"list_items,omitempty"`
+
+	// EventTypes is a comma-separated list of eventTypeNames the activity
+	// feed is limited to, e.g. "push,pr"
+	EventTypes string `json:"event_types,omitempty"`
+}
+
+// minInterval keeps the auto-refresh from hammering the API
+const minInterval = 10 * time.Second
+
+// interval parses Interval, 0 meaning no auto-refresh
+func (c Config) interval() (time.Duration, error) {
+	if c.Interval == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(c.Interval)
+	if err != nil {
+		return 0, fmt.Errorf("invalid interval %q", c.Interval)
+	}
+	if d < minInterval {
+		return 0, fmt.Errorf("interval %v is below the %v minimum", d, minInterval)
+	}
+	return d, nil
+}
+
+// defaultNotifDuration applies when no notification duration is configured
+const defaultNotifDuration = 3 * time.Second
+
+// notifDuration parses NotifDuration, falling back to the default
+func (c Config) notifDuration() (time.Duration, error) {
+	if c.NotifDuration == "" {
+		return defaultNotifDuration, nil
+	}
+	d, err := time.ParseDuration(c.NotifDuration)
+	if err != nil || d <= 0 {
+		return defaultNotifDuration, fmt.Errorf("invalid notification duration %q, using %v", c.NotifDuration, defaultNotifDuration)
+	}
+	return d, nil
+}
+
+// datePresets maps the named date formats to their layout. "relative"
+// is handled by formatDate.
+var datePresets = map[string]string{
+	"iso":      "2006-01-02",
+	"us":       "01/02/2006",
+	"relative": relativeDate,
+}
+
+// dateLayout resolves DateFormat to a layout, falling back to "iso".
+// A custom layout must contain at least one date or time element.
+func (c Config) dateLayout() (string, error) {
+	if c.DateFormat == "" {
+		return datePresets["iso"], nil
+	}
+	if layout, ok := datePresets[c.DateFormat]; ok {
+		return layout, nil
+	}
+	if time.Date(2001, 2, 3, 4, 5, 6, 0, time.UTC).Format(c.DateFormat) == c.DateFormat {
+		return datePresets["iso"], fmt.Errorf("invalid date format %q, using iso (presets: iso, us, relative, or a Go layout like \"02 Jan 2006\")", c.DateFormat)
+	}
+	return c.DateFormat, nil
+}
+
+// location returns the configured timezone, falling back to local time
+func (c Config) location() (*time.Location, error) {
+	if c.Timezone == "" {
+		return time.Local, nil
+	}
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		return time.Local, fmt.Errorf("unknown timezone %q, using local time", c.Timezone)
+	}
+	return loc, nil
+}
+
+// configPath returns the location of the config file,
+// e.g. ~/.config/gitact/config.json on Linux
+func configPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "gitact", "config.json"), nil
+}
+
+// ghHostsPath returns the hosts file of the gh CLI, which honours
+// GH_CONFIG_DIR, then XDG_CONFIG_HOME, then ~/.config on every OS but Windows
+func ghHostsPath() (string, error) {
+	if dir := os.Getenv("GH_CONFIG_DIR"); dir != "" {
+		return filepath.Join(dir, "hosts.yml"), nil
+	}
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "gh", "hosts.yml"), nil
+	}
+	if dir := os.Getenv("AppData"); dir != "" && runtime.GOOS == "windows" {
+		return filepath.Join(dir, "GitHub CLI", "hosts.yml"), nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", "gh", "hosts.yml"), nil
+}
+
+// ghCLIToken returns the github.com token saved by "gh auth login", or ""
+// when there is none. Recent gh versions keep it in the system keyring
+// instead, in which case the file has no token.
+func ghCLIToken() string {
+	path, err := ghHostsPath()
+	if err != nil {
+		return ""
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return parseGHHostsToken(string(data), "github.com")
+}
+
+// parseGHHostsToken reads oauth_token from the host block of a gh
+// hosts.yml. Only the host's direct fields are considered, nested
+// per-user entries are skipped. This is not a general YAML parser, it
+// handles the simple mapping gh writes.
+func parseGHHostsToken(data, host string) string {
+	inHost := false
+	fieldIndent := -1
+
+	for _, line := range strings.Split(data, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+
+		if indent == 0 {
+			key, _, _ := strings.Cut(trimmed, ":")
+			inHost = strings.Trim(key, `"'`) == host
+			fieldIndent = -1
+			continue
+		}
+		if !inHost {
+			continue
+		}
+		// the first field sets the indentation of the host's own fields
+		if fieldIndent < 0 {
+			fieldIndent = indent
+		}
+		if indent != fieldIndent {
+			continue
+		}
+
+		key, value, ok := strings.Cut(trimmed, ":")
+		if ok && strings.TrimSpace(key) == "oauth_token" {
+			return strings.Trim(strings.TrimSpace(value), `"'`)
+		}
+	}
+	return ""
+}
+
+// loadConfig reads the config file. A missing file is not an error,
+// the zero Config is returned and defaults apply.
+func loadConfig() (Config, error) {
+	var cfg Config
+
+	path, err := configPath()
+	if err != nil {
+		return cfg, fmt.Errorf("error locating config: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cfg, nil
+	} else if err != nil {
+		return cfg, fmt.Errorf("error reading config: %v", err)
+	}
+
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return Config{}, fmt.Errorf("error parsing config %s: %v", path, err)
+	}
+
+	return cfg, nil
+}
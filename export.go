@@ -0,0 +1,259 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"gitact/pkg/github"
+)
+
+// repoFieldNames lists the exportable repository fields in their default order
+var repoFieldNames = []string{
+	"name", "full_name", "description", "url", "clone_url", "homepage",
+	"stars", "forks", "language", "fork", "default_branch", "created_at", "updated_at",
+}
+
+// repoFields maps an export field name to its value in a PublicRepo
+var repoFields = map[string]func(PublicRepo) any{
+	"name":           func(r PublicRepo) any { return r.Name },
+	"full_name":      func(r PublicRepo) any { return r.FullName },
+	"description":    func(r PublicRepo) any { return r.Description },
+	"url":            func(r PublicRepo) any { return r.URL },
+	"clone_url":      func(r PublicRepo) any { return r.CloneURL },
+	"homepage":       func(r PublicRepo) any { return r.Homepage },
+	"stars":          func(r PublicRepo) any { return r.Stars },
+	"forks":          func(r PublicRepo) any { return r.Forks },
+	"language":       func(r PublicRepo) any { return r.Language },
+	"fork":           func(r PublicRepo) any { return r.Fork },
+	"default_branch": func(r PublicRepo) any { return r.DefaultBranch },
+	"created_at":     func(r PublicRepo) any { return r.CreatedAt },
+	"updated_at":     func(r PublicRepo) any { return r.UpdatedAt },
+}
+
+// parseFields turns a comma-separated --fields value into field names,
+// keeping the given order. An empty value selects every field.
+func parseFields(raw string) ([]string, error) {
+	if strings.TrimSpace(raw) == "" {
+		return repoFieldNames, nil
+	}
+
+	var fields []string
+	for _, name := range strings.Split(raw, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if _, ok := repoFields[name]; !ok {
+			return nil, fmt.Errorf("unknown field %q (valid fields: %s)", name, strings.Join(repoFieldNames, ", "))
+		}
+		fields = append(fields, name)
+	}
+
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("--fields needs at least one field name")
+	}
+	return fields, nil
+}
+
+// writeReposJSON writes repos as a JSON array of objects whose keys
+// follow the order of fields
+func writeReposJSON(w io.Writer, repos []PublicRepo, fields []string) error {
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	for i, repo := range repos {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		buf.WriteByte('{')
+		for j, field := range fields {
+			if j > 0 {
+				buf.WriteByte(',')
+			}
+			key, _ := json.Marshal(field)
+			value, err := json.Marshal(repoFields[field](repo))
+			if err != nil {
+				return fmt.Errorf("error encoding %s: %v", field, err)
+			}
+			buf.Write(key)
+			buf.WriteByte(':')
+			buf.Write(value)
+		}
+		buf.WriteByte('}')
+	}
+	buf.WriteByte(']')
+
+	var out bytes.Buffer
+	if err := json.Indent(&out, buf.Bytes(), "", "  "); err != nil {
+		return fmt.Errorf("error formatting JSON: %v", err)
+	}
+	out.WriteByte('\n')
+	_, err := out.WriteTo(w)
+	return err
+}
+
+// writeReposCSV writes repos as CSV with a header row of field names
+func writeReposCSV(w io.Writer, repos []PublicRepo, fields []string) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(fields); err != nil {
+		return err
+	}
+
+	for _, repo := range repos {
+		record := make([]string, len(fields))
+		for i, field := range fields {
+			record[i] = csvValue(repoFields[field](repo))
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func csvValue(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case int:
+		return strconv.Itoa(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case time.Time:
+		return v.Format(time.RFC3339)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// parseRepoTemplate compiles a --format template. \t and \n are turned
+// into tabs and newlines, as shells pass them literally. Running it on an
+// empty repository catches unknown fields before anything is fetched.
+func parseRepoTemplate(format string) (*template.Template, error) {
+	format = strings.NewReplacer(`\t`, "\t", `\n`, "\n").Replace(format)
+	tmpl, err := template.New("format").Parse(format)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --format template: %v", err)
+	}
+	if err := tmpl.Execute(io.Discard, PublicRepo{}); err != nil {
+		return nil, fmt.Errorf("invalid --format template: %v", err)
+	}
+	return tmpl, nil
+}
+
+// writeReposTemplate returns a writer for exportPublicRepos executing
+// tmpl once per repository, each on its own line
+func writeReposTemplate(tmpl *template.Template) func(io.Writer, []PublicRepo, []string) error {
+	return func(w io.Writer, repos []PublicRepo, _ []string) error {
+		for _, repo := range repos {
+			if err := tmpl.Execute(w, repo); err != nil {
+				return err
+			}
+			if _, err := io.WriteString(w, "\n"); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// activityEntry is one event of the --activity --json export
+type activityEntry struct {
+	Type      string    `json:"type"`
+	Repo      string    `json:"repo"`
+	Actor     string    `json:"actor"`
+	Summary   string    `json:"summary"`
+	CreatedAt time.Time `json:"created_at"`
+	// Commits is only filled with --include-commits, pushes can carry
+	// dozens of them
+	Commits []github.Commit `json:"commits,omitempty"`
+}
+
+// writeActivityJSON writes events as a JSON array, with the commits of
+// PushEvents when includeCommits is set
+func writeActivityJSON(w io.Writer, events []GitHubEvent, includeCommits bool) error {
+	entries := make([]activityEntry, 0, len(events))
+	for _, event := range events {
+		entry := activityEntry{
+			Type:      event.Type,
+			Repo:      event.Repo.Name,
+			Actor:     event.Actor.Login,
+			Summary:   eventSummary(event),
+			CreatedAt: event.CreatedAt,
+		}
+		if includeCommits {
+			entry.Commits = event.Payload.Commits
+		}
+		entries = append(entries, entry)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(entries)
+}
+
+// statsSummary is the serializable form of the stats view
+type statsSummary struct {
+	Username     string         `json:"username"`
+	Repos        int            `json:"repos"`
+	Stars        int            `json:"stars"`
+	Forks        int            `json:"forks"`
+	Languages    map[string]int `json:"languages"`
+	TopRepos     []topRepo      `json:"top_repos"`
+	Grade        string         `json:"grade"`
+	Events       int            `json:"events"`
+	PushEvents   int            `json:"push_events"`
+	PullRequests int            `json:"pull_request_events"`
+	IssueEvents  int            `json:"issue_events"`
+	CreateEvents int            `json:"create_events"`
+	WatchEvents  int            `json:"watch_events"`
+}
+
+// topRepo is a repository of statsSummary.TopRepos
+type topRepo struct {
+	Name  string `json:"name"`
+	Stars int    `json:"stars"`
+}
+
+// statsTopRepos is the number of repositories in statsSummary.TopRepos
+const statsTopRepos = 5
+
+// buildStatsSummary aggregates repos and stats like the stats view
+func buildStatsSummary(username string, repos []PublicRepo, stats GitHubStats) statsSummary {
+	s := statsSummary{
+		Username:     username,
+		Repos:        len(repos),
+		Languages:    make(map[string]int),
+		TopRepos:     []topRepo{},
+		Grade:        getGrade(stats),
+		Events:       stats.TotalEvents,
+		PushEvents:   stats.PushEvents,
+		PullRequests: stats.PullRequestEvents,
+		IssueEvents:  stats.IssueEvents,
+		CreateEvents: stats.CreateEvents,
+		WatchEvents:  stats.WatchEvents,
+	}
+	for _, repo := range repos {
+		s.Stars += repo.Stars
+		s.Forks += repo.Forks
+		if repo.Language != "" {
+			s.Languages[repo.Language]++
+		}
+	}
+
+	byStars := append([]PublicRepo(nil), repos...)
+	sort.SliceStable(byStars, func(i, j int) bool { return byStars[i].Stars > byStars[j].Stars })
+	for _, repo := range byStars[:min(len(byStars), statsTopRepos)] {
+		s.TopRepos = append(s.TopRepos, topRepo{Name: repo.Name, Stars: repo.Stars})
+	}
+	return s
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"gitact/pkg/github"
+)
+
+// gradeReport is the JSON of --grade --json
+type gradeReport struct {
+	Username  string             `json:"username"`
+	Grade     string             `json:"grade"`
+	Score     float64            `json:"score"`
+	Events    int                `json:"events"`
+	Breakdown map[string]float64 `json:"breakdown"`
+}
+
+// showGrade runs --grade, printing the activity grade and the points
+// each event type contributed to it
+func showGrade(client *github.Client, username string, cfg Config, jsonOutput bool) {
+	events, err := client.FetchActivity(context.Background(), username, cfg.PublicOnly)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error fetching activity: %v\n", err)
+		os.Exit(exitCode(err))
+	}
+	// same events as the grade of the dashboard
+	stats := calculateStats(events)
+	if cfg.LimitStats {
+		stats = calculateStats(feedEvents(events, cfg))
+	}
+
+	if jsonOutput {
+		report := gradeReport{
+			Username:  username,
+			Grade:     getGrade(stats),
+			Score:     gradeScore(stats),
+			Events:    stats.TotalEvents,
+			Breakdown: gradeBreakdown(stats),
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			fmt.Fprintf(os.Stderr, "error writing output: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	fmt.Printf("Activity Grade: %s (%d events)\n", getGrade(stats), stats.TotalEvents)
+	writeGradeBreakdown(os.Stdout, stats, "   ")
+}
+
+// writeGradeBreakdown writes a line per event type that scored, as
+// "Pull Request Events: 18 of 42 points", most valued types first
+func writeGradeBreakdown(w io.Writer, stats GitHubStats, indent string) {
+	score := gradeScore(stats)
+	breakdown := gradeBreakdown(stats)
+	for _, c := range gradeComponents {
+		if breakdown[c.key] == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "%s%s: %s of %s points\n", indent, c.label, formatPoints(breakdown[c.key]), formatPoints(score))
+	}
+}
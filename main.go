@@ -1,78 +1,627 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"os"
 	"strings"
+	"text/template"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"gitact/pkg/github"
 )
 
 func main() {
-	if len(os.Args) < 2 {
-		showUsage()
+	var (
+		reposMode   bool
+		helpFlag    bool
+		versionFlag bool
+		proxy       string
+		jsonOutput  bool
+		csvOutput   bool
+		fieldList   string
+		countOnly   bool
+		publicOnly  bool
+		notifDur    string
+		actLimit    int
+		limitStats  bool
+		compareMode bool
+		dateFormat  string
+		badgeMode   bool
+		output      string
+		noRateCheck bool
+		noEmojiFlag bool
+		format      string
+		sinceFlag   string
+		interval    string
+		minStars    int
+		orgsMode    bool
+		maxPages    int
+		actMode     bool
+		withCommits bool
+		plainMode   bool
+		accent      string
+		collapse    bool
+		hasFile     string
+		nativeFilt  bool
+		noMouse     bool
+		listItems   int
+		gradeMode   bool
+		summaryMode bool
+		eventTypes  string
+	)
+	flag.BoolVar(&reposMode, "repos", false, "list public repositories and exit")
+	flag.BoolVar(&helpFlag, "h", false, "show help")
+	flag.BoolVar(&helpFlag, "help", false, "show help")
+	flag.BoolVar(&versionFlag, "v", false, "show version")
+	flag.BoolVar(&versionFlag, "version", false, "show version")
+	flag.StringVar(&proxy, "proxy", "", "proxy URL for GitHub API requests")
+	flag.IntVar(&maxPages, "max-pages", 10, "stop listing repositories after N pages of 100, 0 for no limit")
+	flag.BoolVar(&orgsMode, "orgs", false, "list the user's public organizations and exit")
+	flag.BoolVar(&badgeMode, "badge", false, "write an SVG badge with the activity grade and stars and exit")
+	flag.StringVar(&output, "output", "", "with --badge, file to write instead of stdout")
+	flag.BoolVar(&actMode, "activity", false, "print the recent activity and exit")
+	flag.BoolVar(&gradeMode, "grade", false, "print the activity grade and what each event type added to it, and exit")
+	flag.BoolVar(&withCommits, "include-commits", false, "with --activity --json, include the commits of each push")
+	flag.BoolVar(&summaryMode, "summary", false, "with --json, print the profile, repository totals, languages and grade as one JSON document")
+	flag.BoolVar(&plainMode, "plain", false, "print a text dashboard instead of starting the interactive one")
+	flag.BoolVar(&compareMode, "compare", false, "compare the totals of two users and exit")
+	flag.BoolVar(&jsonOutput, "json", false, "with --repos, --activity, --grade, --summary or --compare, print JSON")
+	flag.BoolVar(&csvOutput, "csv", false, "with --repos, print repositories as CSV")
+	flag.StringVar(&format, "format", "", "with --repos, Go template printed for each repository")
+	flag.StringVar(&fieldList, "fields", "", "comma-separated fields for --json/--csv")
+	flag.StringVar(&sinceFlag, "updated-since", "", "with --repos, only include repositories updated since a date or within a window (30d)")
+	flag.StringVar(&hasFile, "has-file", "", "with --repos, only include repositories containing a file or directory (e.g. go.mod)")
+	flag.IntVar(&minStars, "min-stars", 0, "with --repos, only include repositories with at least N stars")
+	flag.BoolVar(&countOnly, "count", false, "with --repos, print only the number of public repositories")
+	flag.BoolVar(&countOnly, "count-only", false, "same as --count")
+	flag.BoolVar(&publicOnly, "public-only", false, "only show public activity events")
+	flag.StringVar(&dateFormat, "date-format", "", "date format: iso, us, relative or a Go layout")
+	flag.StringVar(&notifDur, "notif-duration", "", "how long notifications stay visible (e.g. 5s)")
+	flag.IntVar(&actLimit, "limit-activity", 0, "only show the N most recent activity events")
+	flag.BoolVar(&limitStats, "limit-stats", false, "with --limit-activity, compute stats on the limited events only")
+	flag.StringVar(&eventTypes, "event-types", "", "comma-separated event types the activity feed shows (e.g. push,pr)")
+	flag.BoolVar(&nativeFilt, "native-filter", false, "fuzzy filter the lists on / instead of the search bar")
+	flag.BoolVar(&collapse, "collapse", false, "merge consecutive pushes to the same repository in the activity feed")
+	flag.StringVar(&accent, "accent", "", "hex color of the header, titles and selection (e.g. #7aa2f7)")
+	flag.BoolVar(&noMouse, "no-mouse", false, "leave the mouse to the terminal, for selecting text")
+	flag.IntVar(&listItems, "items", 0, "show at most N items per page in the lists, 0 to fit the terminal")
+	flag.BoolVar(&noEmojiFlag, "no-emoji", false, "leave emoji out of notifications")
+	flag.StringVar(&interval, "interval", "", "refresh the dashboard periodically (e.g. 5m, at least 10s)")
+	flag.BoolVar(&noRateCheck, "no-rate-check", false, "don't check the rate limit before starting the dashboard")
+	flag.Usage = showUsage
+	flag.Parse()
+
+	// flags
+	switch {
+	case helpFlag:
+		showHelp()
+		return
+	case versionFlag:
+		showVersion()
+		return
+	}
+
+	// Load user settings, falling back to defaults on any problem
+	cfg, err := loadConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Config warning: %v\n", err)
+	}
+	if publicOnly {
+		cfg.PublicOnly = true
+	}
+	if notifDur != "" {
+		cfg.NotifDuration = notifDur
+	}
+	if actLimit < 0 {
+		fmt.Fprintf(os.Stderr, "error: --limit-activity must be positive\n")
+		os.Exit(1)
+	}
+	if actLimit > 0 {
+		cfg.ActivityLimit = actLimit
+	}
+	if limitStats {
+		cfg.LimitStats = true
+	}
+	if listItems < 0 {
+		fmt.Fprintf(os.Stderr, "error: --items must be positive\n")
+		os.Exit(1)
+	}
+	if listItems > 0 {
+		cfg.ListItems = listItems
+	}
+	if noRateCheck {
+		cfg.NoRateCheck = true
+	}
+	if noEmojiFlag {
+		cfg.NoEmoji = true
+	}
+	noEmoji = cfg.NoEmoji
+	if _, err := cfg.location(); err != nil {
+		fmt.Fprintf(os.Stderr, "Config warning: %v\n", err)
+	}
+	if _, err := cfg.notifDuration(); err != nil {
+		fmt.Fprintf(os.Stderr, "Config warning: %v\n", err)
+	}
+	if interval != "" {
+		cfg.Interval = interval
+	}
+	if _, err := cfg.interval(); err != nil {
+		// a bad flag is a mistake worth stopping for, unlike the config file
+		if interval != "" {
+			fmt.Fprintf(os.Stderr, "error: --interval: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Fprintf(os.Stderr, "Config warning: %v, auto-refresh is off\n", err)
+	}
+	if collapse {
+		cfg.Collapse = true
+	}
+	if eventTypes != "" {
+		cfg.EventTypes = eventTypes
+	}
+	if _, err := parseEventTypes(cfg.EventTypes); err != nil {
+		if eventTypes != "" {
+			fmt.Fprintf(os.Stderr, "error: --event-types: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Fprintf(os.Stderr, "Config warning: event_types: %v, showing every event\n", err)
+		cfg.EventTypes = ""
+	}
+	if nativeFilt {
+		cfg.NativeFilter = true
+	}
+	if noMouse {
+		cfg.NoMouse = true
+	}
+	if accent != "" {
+		cfg.Accent = accent
+	}
+	var accentColor lipgloss.Color
+	if cfg.Accent != "" {
+		if accentColor, err = parseAccent(cfg.Accent); err != nil {
+			if accent != "" {
+				fmt.Fprintf(os.Stderr, "error: --accent: %v\n", err)
+				os.Exit(1)
+			}
+			fmt.Fprintf(os.Stderr, "Config warning: %v\n", err)
+		}
+	}
+	if dateFormat != "" {
+		cfg.DateFormat = dateFormat
+	}
+	if dateLayout, err = cfg.dateLayout(); err != nil {
+		fmt.Fprintf(os.Stderr, "Config warning: %v\n", err)
+	}
+
+	if proxy == "" {
+		proxy = os.Getenv("GITACT_PROXY")
+	}
+	if err = setProxy(proxy); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+	if maxPages < 0 {
+		fmt.Fprintf(os.Stderr, "error: --max-pages can't be negative\n")
+		os.Exit(1)
+	}
+	client := newClient()
+	client.MaxPages = maxPages
+
+	if jsonOutput && csvOutput {
+		fmt.Fprintf(os.Stderr, "error: --json and --csv can't be used together\n")
+		os.Exit(1)
+	}
+	if reposMode && compareMode {
+		fmt.Fprintf(os.Stderr, "error: --repos and --compare can't be used together\n")
+		os.Exit(1)
+	}
+	if badgeMode && (reposMode || compareMode || jsonOutput || csvOutput) {
+		fmt.Fprintf(os.Stderr, "error: --badge can't be combined with --repos, --compare, --json or --csv\n")
+		os.Exit(1)
+	}
+	if output != "" && !badgeMode {
+		fmt.Fprintf(os.Stderr, "error: --output requires --badge\n")
+		os.Exit(1)
+	}
+	if actMode && (reposMode || compareMode || badgeMode) {
+		fmt.Fprintf(os.Stderr, "error: --activity can't be combined with --repos, --compare or --badge\n")
+		os.Exit(1)
+	}
+	if gradeMode && (reposMode || compareMode || badgeMode || actMode || orgsMode) {
+		fmt.Fprintf(os.Stderr, "error: --grade can't be combined with --repos, --compare, --badge, --activity or --orgs\n")
+		os.Exit(1)
+	}
+	if summaryMode && (reposMode || compareMode || badgeMode || actMode || orgsMode || gradeMode || !jsonOutput) {
+		fmt.Fprintf(os.Stderr, "error: --summary requires --json and no other mode\n")
+		os.Exit(1)
+	}
+	if plainMode && (reposMode || compareMode || badgeMode || actMode || orgsMode || gradeMode || summaryMode || jsonOutput || csvOutput) {
+		fmt.Fprintf(os.Stderr, "error: --plain replaces the dashboard and can't be combined with another mode\n")
+		os.Exit(1)
+	}
+	if jsonOutput && !reposMode && !compareMode && !actMode && !gradeMode && !summaryMode {
+		fmt.Fprintf(os.Stderr, "error: --json requires --repos, --activity, --grade, --summary or --compare\n")
+		os.Exit(1)
+	}
+	if withCommits && (!actMode || !jsonOutput) {
+		fmt.Fprintf(os.Stderr, "error: --include-commits requires --activity --json\n")
+		os.Exit(1)
+	}
+	if csvOutput && !reposMode {
+		fmt.Fprintf(os.Stderr, "error: --csv requires --repos\n")
+		os.Exit(1)
+	}
+	if minStars < 0 {
+		fmt.Fprintf(os.Stderr, "error: --min-stars can't be negative\n")
+		os.Exit(1)
+	}
+	if minStars > 0 && !reposMode {
+		fmt.Fprintf(os.Stderr, "error: --min-stars requires --repos\n")
+		os.Exit(1)
+	}
+	if sinceFlag != "" && !reposMode {
+		fmt.Fprintf(os.Stderr, "error: --updated-since requires --repos\n")
+		os.Exit(1)
+	}
+	if hasFile != "" && !reposMode {
+		fmt.Fprintf(os.Stderr, "error: --has-file requires --repos\n")
+		os.Exit(1)
+	}
+	filter := repoFilter{minStars: minStars, hasFile: strings.Trim(hasFile, "/")}
+	if sinceFlag != "" {
+		if filter.updatedSince, err = parseSince(sinceFlag, time.Now()); err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	if countOnly && !reposMode {
+		fmt.Fprintf(os.Stderr, "error: --count requires --repos\n")
+		os.Exit(1)
+	}
+	if fieldList != "" && (!reposMode || !jsonOutput && !csvOutput) {
+		fmt.Fprintf(os.Stderr, "error: --fields requires --repos with --json or --csv\n")
+		os.Exit(1)
+	}
+	fields, err := parseFields(fieldList)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+	if format != "" && (!reposMode || jsonOutput || csvOutput || countOnly || fieldList != "") {
+		fmt.Fprintf(os.Stderr, "error: --format requires --repos and replaces --json, --csv, --count and --fields\n")
+		os.Exit(1)
+	}
+	var tmpl *template.Template
+	if format != "" {
+		if tmpl, err = parseRepoTemplate(format); err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	if flag.NArg() < 1 {
+		if reposMode {
+			fmt.Fprintf(os.Stderr, "error: --repos requires a username\n")
+			fmt.Fprintf(os.Stderr, "usage: %s --repos <username>\n", os.Args[0])
+		} else {
+			showUsage()
+		}
 		os.Exit(1)
 	}
 
-	// Check if --repos flag is used
-	if len(os.Args) >= 3 && os.Args[1] == "--repos" {
-		username := strings.TrimSpace(os.Args[2])
-		if username == "" {
-			fmt.Fprintf(os.Stderr, "error: username can't be empty\n")
+	var usernames []string
+	for _, arg := range flag.Args() {
+		u, err := normalizeUsername(arg)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
 			os.Exit(1)
 		}
-		showPublicRepos(username)
+		usernames = append(usernames, u)
+	}
+	username := usernames[0]
+	if reposMode && len(usernames) > 1 {
+		fmt.Fprintf(os.Stderr, "error: --repos takes a single username\n")
+		os.Exit(1)
+	}
+	if actMode && len(usernames) > 1 {
+		fmt.Fprintf(os.Stderr, "error: --activity takes a single username\n")
+		os.Exit(1)
+	}
+	if gradeMode && len(usernames) > 1 {
+		fmt.Fprintf(os.Stderr, "error: --grade takes a single username\n")
+		os.Exit(1)
+	}
+	if summaryMode && len(usernames) > 1 {
+		fmt.Fprintf(os.Stderr, "error: --summary takes a single username\n")
+		os.Exit(1)
+	}
+
+	if orgsMode {
+		if reposMode || compareMode || badgeMode || actMode || len(usernames) != 1 {
+			fmt.Fprintf(os.Stderr, "error: --orgs takes a single username and no other mode\n")
+			os.Exit(1)
+		}
+		showOrgs(client, username)
 		return
 	}
 
-	username := strings.TrimSpace(os.Args[1])
+	if badgeMode {
+		if len(usernames) != 1 {
+			fmt.Fprintf(os.Stderr, "error: --badge takes a single username\n")
+			os.Exit(1)
+		}
+		showBadge(client, username, output)
+		return
+	}
 
-	// flags
-	switch username {
-	case "-h", "--help":
-		showHelp()
+	if compareMode {
+		if len(usernames) != 2 {
+			fmt.Fprintf(os.Stderr, "error: --compare takes two usernames\n")
+			os.Exit(1)
+		}
+		showComparison(client, usernames, jsonOutput)
 		return
-	case "-v", "--version":
-		showVersion()
+	}
+
+	if actMode {
+		showActivity(client, username, cfg, jsonOutput, withCommits)
 		return
-	case "--repos":
-		fmt.Fprintf(os.Stderr, "error: --repos requires a username\n")
-		fmt.Fprintf(os.Stderr, "usage: %s --repos <username>\n", os.Args[0])
-		os.Exit(1)
 	}
 
-	if username == "" {
-		fmt.Fprintf(os.Stderr, "error: username can't be empty\n")
-		os.Exit(1)
+	if gradeMode {
+		showGrade(client, username, cfg, jsonOutput)
+		return
 	}
 
-	// Check rate limit before starting
-	if err := checkRateLimit(); err != nil {
-		fmt.Fprintf(os.Stderr, "Rate limit warning: %v\n", err)
-		fmt.Fprintf(os.Stderr, "Set GITHUB_TOKEN environment variable for higher limits\n\n")
+	if summaryMode {
+		showSummary(client, username, cfg)
+		return
 	}
 
-	// init model bubble tea with new modernized UI
-	initialModel := NewModel(username)
+	if reposMode {
+		switch {
+		case countOnly:
+			showRepoCount(client, username, filter)
+		case tmpl != nil:
+			exportPublicRepos(client, username, writeReposTemplate(tmpl), nil, filter)
+		case jsonOutput:
+			exportPublicRepos(client, username, writeReposJSON, fields, filter)
+		case csvOutput:
+			exportPublicRepos(client, username, writeReposCSV, fields, filter)
+		default:
+			showPublicRepos(client, username, filter)
+		}
+		return
+	}
+
+	// the TUI garbles a pipe or a file, print the text dashboard there
+	if !plainMode && !isTerminal(os.Stdout) {
+		fmt.Fprintf(os.Stderr, "stdout is not a terminal, printing the plain dashboard\n")
+		plainMode = true
+	}
+	if plainMode {
+		for i, u := range usernames {
+			if i > 0 {
+				fmt.Println()
+			}
+			showPlain(client, u, cfg)
+		}
+		return
+	}
+
+	// Check rate limit before starting. The non-interactive modes above
+	// skip it so scripts don't pay for the extra request.
+	if !cfg.NoRateCheck {
+		rate, err := checkRateLimit(client)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Rate limit warning: %v\n", err)
+			if errors.Is(err, github.ErrBadToken) {
+				fmt.Fprintf(os.Stderr, "Check GITHUB_TOKEN, or run gh auth login again\n\n")
+			} else {
+				fmt.Fprintf(os.Stderr, "Set GITHUB_TOKEN environment variable for higher limits\n\n")
+			}
+		}
+		if rate.Limit > 0 && rate.Remaining == 0 && !waitForRateLimit(rate, os.Stdin) {
+			os.Exit(exitRateLimited)
+		}
+	}
+
+	var warnings []string
+	keys, warnings = buildKeyMap(cfg.Keys)
+	for _, w := range warnings {
+		fmt.Fprintf(os.Stderr, "Config warning: %s\n", w)
+	}
 
-	p := tea.NewProgram(initialModel, tea.WithAltScreen(), tea.WithMouseCellMotion())
-	if _, err := p.Run(); err != nil {
+	// Pick colors the terminal can actually show
+	setupPalette()
+	if accentColor != "" {
+		setAccent(accentColor)
+	}
+
+	// init model bubble tea with new modernized UI, several users start
+	// on a selector
+	var initialModel tea.Model = NewModel(client, username, cfg)
+	if len(usernames) > 1 {
+		initialModel = NewSelectorModel(client, usernames, cfg)
+	}
+
+	opts := []tea.ProgramOption{tea.WithAltScreen()}
+	// mouse reporting keeps the terminal from selecting text
+	if !cfg.NoMouse {
+		opts = append(opts, tea.WithMouseCellMotion())
+	}
+	p := tea.NewProgram(initialModel, opts...)
+	finalModel, err := p.Run()
+	if err != nil {
 		fmt.Printf("error during the launch : %v", err)
 		os.Exit(1)
 	}
+
+	// remember the newest events seen, to mark the ones after them next time
+	var seen []Model
+	switch fm := finalModel.(type) {
+	case Model:
+		seen = append(seen, fm)
+	case SelectorModel:
+		for _, d := range fm.dashboards {
+			seen = append(seen, d)
+		}
+	}
+	for _, d := range seen {
+		if err := d.saveLastSeen(); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: could not save the last seen event: %v\n", err)
+		}
+	}
 }
 
-func showPublicRepos(username string) {
+// Exit statuses of the command line modes, for scripts
+const (
+	exitError        = 1 // any other failure, including invalid flags
+	exitUserNotFound = 2
+	exitRateLimited  = 3
+)
+
+// exitCode maps the error that stopped a command line mode to its status
+func exitCode(err error) int {
+	switch {
+	case errors.Is(err, github.ErrUserNotFound):
+		return exitUserNotFound
+	case errors.Is(err, github.ErrRateLimited), errors.Is(err, github.ErrSearchRateLimited):
+		return exitRateLimited
+	}
+	return exitError
+}
+
+func showPublicRepos(client *github.Client, username string, filter repoFilter) {
 	fmt.Printf("Fetching public repositories for user: %s\n", username)
 
 	// Fetch public repositories
-	publicRepos, err := fetchPublicRepos(username)
+	publicRepos, hidden, err := listRepos(client, username, filter)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "❌ Error fetching public repositories: %v\n", err)
-		os.Exit(1)
+		os.Exit(exitCode(err))
 	}
 
 	// Display statistics and repositories
 	calculatePublicReposStats(publicRepos)
-	printPublicRepos(publicRepos)
+	printPublicRepos(publicRepos, hidden)
+}
+
+// fetchRepos lists the repositories for the command line modes. Hitting
+// --max-pages is only worth a warning on stderr, the partial list is used.
+func fetchRepos(client *github.Client, username string) ([]PublicRepo, error) {
+	repos, err := client.FetchRepos(context.Background(), username)
+	if errors.Is(err, github.ErrPageLimit) {
+		warnPageLimit(client, username, len(repos))
+		return repos, nil
+	}
+	return repos, err
+}
+
+// warnPageLimit tells on stderr that only the first n repositories of
+// username were fetched because of --max-pages
+func warnPageLimit(client *github.Client, username string, n int) {
+	fmt.Fprintf(os.Stderr, "warning: stopped after %d repositories of %s (--max-pages %d), use --max-pages 0 for all\n", n, username, client.MaxPages)
+}
+
+// listRepos fetches the repositories and applies filter, returning how
+// many were left out
+func listRepos(client *github.Client, username string, filter repoFilter) ([]PublicRepo, int, error) {
+	repos, err := fetchRepos(client, username)
+	if err != nil {
+		return nil, 0, err
+	}
+	kept, hidden := filter.apply(repos)
+	if filter.hasFile != "" {
+		withFile, err := filterHasFile(client, kept, filter.hasFile)
+		if err != nil {
+			return nil, 0, err
+		}
+		hidden += len(kept) - len(withFile)
+		kept = withFile
+	}
+	return kept, hidden, nil
+}
+
+func showOrgs(client *github.Client, username string) {
+	orgs, err := client.FetchUserOrgs(context.Background(), username)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Error fetching organizations: %v\n", err)
+		os.Exit(exitCode(err))
+	}
+	printOrgs(username, orgs)
+}
+
+// showActivity prints the recent events, following the --public-only and
+// --limit-activity settings of the dashboard
+func showActivity(client *github.Client, username string, cfg Config, jsonOutput, includeCommits bool) {
+	events, err := client.FetchActivity(context.Background(), username, cfg.PublicOnly)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error fetching activity: %v\n", err)
+		os.Exit(exitCode(err))
+	}
+	events = feedEvents(events, cfg)
+
+	if jsonOutput {
+		if err := writeActivityJSON(os.Stdout, events, includeCommits); err != nil {
+			fmt.Fprintf(os.Stderr, "error writing output: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	// the warning for an unknown timezone was already printed at startup
+	loc, _ := cfg.location()
+	if cfg.Collapse {
+		for _, c := range collapseEvents(events, collapseWindow) {
+			fmt.Printf("%s  %s\n", formatDateTime(c.event.CreatedAt.In(loc)), formatCollapsedShort(c, username))
+		}
+		return
+	}
+	for _, event := range events {
+		fmt.Printf("%s  %s\n", formatDateTime(event.CreatedAt.In(loc)), formatEventShort(event, username))
+	}
+}
+
+// showRepoCount prints the number of public repositories, read from the
+// profile so a single request is enough. With filters the repositories
+// have to be listed to count them.
+func showRepoCount(client *github.Client, username string, filter repoFilter) {
+	if filter.active() {
+		publicRepos, _, err := listRepos(client, username, filter)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error fetching public repositories: %v\n", err)
+			os.Exit(exitCode(err))
+		}
+		fmt.Println(len(publicRepos))
+		return
+	}
+
+	profile, err := client.FetchProfile(context.Background(), username)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error fetching profile: %v\n", err)
+		os.Exit(exitCode(err))
+	}
+	fmt.Println(profile.PublicRepos)
+}
+
+// exportPublicRepos writes the repositories to stdout in a machine-readable
+// format, without any of the human-oriented output
+func exportPublicRepos(client *github.Client, username string, write func(io.Writer, []PublicRepo, []string) error, fields []string, filter repoFilter) {
+	publicRepos, _, err := listRepos(client, username, filter)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error fetching public repositories: %v\n", err)
+		os.Exit(exitCode(err))
+	}
+
+	if err := write(os.Stdout, publicRepos, fields); err != nil {
+		fmt.Fprintf(os.Stderr, "error writing output: %v\n", err)
+		os.Exit(1)
+	}
 }
@@ -0,0 +1,565 @@
+// Package github is a small client for the parts of the GitHub REST API
+// used by gitact: user activity, repositories, profiles and rate limits.
+// Every call takes a context, cancelling it aborts the in-flight request.
+package github
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultBaseURL is the GitHub REST API root
+const DefaultBaseURL = "https://api.github.com"
+
+// ErrNotFound is wrapped by errors for resources the API answered 404 for
+var ErrNotFound = errors.New("not found")
+
+// ErrUserNotFound is wrapped by errors for usernames that don't exist.
+// It wraps ErrNotFound, so checking for either works.
+var ErrUserNotFound = fmt.Errorf("%w", ErrNotFound)
+
+// ErrSearchRateLimited is returned when the search API, which has its own
+// lower rate limit, refuses a request
+var ErrSearchRateLimited = errors.New("search rate limit exceeded, try again in a minute")
+
+// ErrRateLimited is returned when the core rate limit is exhausted, see
+// Client.RateLimit for when it resets
+var ErrRateLimited = errors.New("API rate limit exceeded")
+
+// ErrBadToken is returned when the API answers 401 to a request made
+// with Client.Token
+var ErrBadToken = errors.New("the token was rejected (401), it is invalid, expired or revoked")
+
+// ErrPageLimit is returned with the repositories fetched so far when a
+// listing has more pages than Client.MaxPages allows
+var ErrPageLimit = errors.New("page limit reached, results are incomplete")
+
+// Client talks to the GitHub API. The zero value is not usable,
+// create one with NewClient and adjust the fields as needed.
+type Client struct {
+	// BaseURL is the API root, without trailing slash
+	BaseURL string
+	// Token is sent as the Authorization header when not empty
+	Token string
+	// UserAgent identifies the application to GitHub
+	UserAgent string
+	// HTTPClient performs the requests
+	HTTPClient *http.Client
+	// MaxPages caps the pages of 100 repositories a listing follows,
+	// 0 means no limit
+	MaxPages int
+
+	// profiles caches FetchProfile results by lowercase login, profiles
+	// barely change during a session and several features need them
+	mu       sync.Mutex
+	profiles map[string]Profile
+}
+
+// NewClient returns a client for the public GitHub API
+func NewClient(token string) *Client {
+	return &Client{
+		BaseURL:    DefaultBaseURL,
+		Token:      token,
+		UserAgent:  "gh-act-cli/1.0",
+		HTTPClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// WithToken returns a new client like c but authenticated with token.
+// Changing Token in place would race with requests in flight, which keep
+// using c. The profile cache starts empty.
+func (c *Client) WithToken(token string) *Client {
+	n := NewClient(token)
+	n.BaseURL = c.BaseURL
+	n.UserAgent = c.UserAgent
+	n.HTTPClient = c.HTTPClient
+	n.MaxPages = c.MaxPages
+	return n
+}
+
+// mediaType is the Accept header of every request, the v3 JSON format
+// which includes the topics of repositories
+const mediaType = "application/vnd.github.v3+json"
+
+// apiVersion pins the REST API version, so a new default version can't
+// change the answers under us
+const apiVersion = "2022-11-28"
+
+// newRequest builds a GET request to path with the headers every endpoint
+// needs, so none can be forgotten by a caller
+func (c *Client) newRequest(ctx context.Context, path string) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.BaseURL+path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating the request: %v", err)
+	}
+
+	req.Header.Set("User-Agent", c.UserAgent)
+	req.Header.Set("Accept", mediaType)
+	req.Header.Set("X-GitHub-Api-Version", apiVersion)
+
+	// Add GitHub token if available
+	if c.Token != "" {
+		req.Header.Set("Authorization", "token "+c.Token)
+	}
+	return req, nil
+}
+
+// do sends req and decodes the JSON body into v
+func (c *Client) do(req *http.Request, v any) error {
+	_, err := c.doHeader(req, v)
+	return err
+}
+
+// doHeader is do for callers that also need the response headers, such
+// as the Link header of paginated listings
+func (c *Client) doHeader(req *http.Request, v any) (http.Header, error) {
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request http error: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 404 {
+		return nil, ErrNotFound
+	} else if resp.StatusCode == 401 && c.Token != "" {
+		return nil, ErrBadToken
+	} else if (resp.StatusCode == 403 || resp.StatusCode == 429) &&
+		resp.Header.Get("X-RateLimit-Resource") == "search" {
+		return nil, ErrSearchRateLimited
+	} else if resp.StatusCode == 429 ||
+		resp.StatusCode == 403 && resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		return nil, ErrRateLimited
+	} else if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("http error %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response: %v", err)
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return nil, fmt.Errorf("error parsing JSON: %v", err)
+	}
+	return resp.Header, nil
+}
+
+// hasNextPage reports whether the Link header of a listing points to a
+// next page, e.g. <https://api.github.com/...&page=3>; rel="next"
+func hasNextPage(header http.Header) bool {
+	for _, value := range header.Values("Link") {
+		for _, link := range strings.Split(value, ",") {
+			for _, param := range strings.Split(link, ";")[1:] {
+				if strings.TrimSpace(param) == `rel="next"` {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// notFound names the missing resource in an ErrNotFound error
+func notFound(err error, format string, args ...any) error {
+	if errors.Is(err, ErrNotFound) {
+		return fmt.Errorf(format+" %w", append(args, ErrNotFound)...)
+	}
+	return err
+}
+
+// userNotFound names the missing user in an ErrUserNotFound error
+func userNotFound(err error, username string) error {
+	if errors.Is(err, ErrNotFound) {
+		return fmt.Errorf("user '%s' %w", username, ErrUserNotFound)
+	}
+	return err
+}
+
+// EventsPerPage is the number of events in a page of activity
+const EventsPerPage = 30
+
+// MaxEventPages is the last page of activity the API serves, it keeps
+// only the 300 most recent events
+const MaxEventPages = 300 / EventsPerPage
+
+// FetchActivity returns the recent events of a user. When the token
+// belongs to that user, /events also includes their private events;
+// publicOnly forces /events/public instead.
+func (c *Client) FetchActivity(ctx context.Context, username string, publicOnly bool) ([]Event, error) {
+	return c.FetchActivityPage(ctx, username, publicOnly, 1)
+}
+
+// FetchActivityPage returns one page of the events of FetchActivity, from
+// 1 to MaxEventPages. A page shorter than EventsPerPage is the last one.
+func (c *Client) FetchActivityPage(ctx context.Context, username string, publicOnly bool, page int) ([]Event, error) {
+	path := fmt.Sprintf("/users/%s/events", username)
+	if publicOnly {
+		path += "/public"
+	}
+	path += fmt.Sprintf("?per_page=%d&page=%d", EventsPerPage, page)
+
+	req, err := c.newRequest(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []Event
+	if err := c.do(req, &events); err != nil {
+		return nil, userNotFound(err, username)
+	}
+	return events, nil
+}
+
+// FetchRepos returns every public repository of a user, most starred first
+func (c *Client) FetchRepos(ctx context.Context, username string) ([]Repository, error) {
+	return c.fetchRepoPages(ctx, fmt.Sprintf("/users/%s/repos?type=public&sort=stars&direction=desc", username), username)
+}
+
+// FetchSubscriptions returns the public repositories a user is watching
+func (c *Client) FetchSubscriptions(ctx context.Context, username string) ([]Repository, error) {
+	return c.fetchRepoPages(ctx, fmt.Sprintf("/users/%s/subscriptions?", username), username)
+}
+
+// fetchRepoPages follows the pages of a repository listing of username.
+// path already holds the query string, the page parameters are appended.
+func (c *Client) fetchRepoPages(ctx context.Context, path, username string) ([]Repository, error) {
+	var allRepos []Repository
+	page := 1
+	perPage := 100
+
+	for {
+		req, err := c.newRequest(ctx, fmt.Sprintf("%s&per_page=%d&page=%d", path, perPage, page))
+		if err != nil {
+			return nil, err
+		}
+
+		var repos []Repository
+		header, err := c.doHeader(req, &repos)
+		if err != nil {
+			return nil, userNotFound(err, username)
+		}
+
+		// No repos returned, we reached the end
+		if len(repos) == 0 {
+			break
+		}
+
+		// Filter only public repositories and add to collection
+		for _, repo := range repos {
+			if !repo.Private {
+				allRepos = append(allRepos, repo)
+			}
+		}
+
+		// Fewer repos than requested, this was the last page
+		if len(repos) < perPage {
+			break
+		}
+		if c.MaxPages > 0 && page >= c.MaxPages {
+			// a full last page is only cut short if the API has another
+			if hasNextPage(header) {
+				return allRepos, ErrPageLimit
+			}
+			break
+		}
+
+		page++
+	}
+
+	return allRepos, nil
+}
+
+// FetchProfile returns the public profile of a user or organization.
+// Profiles are fetched once and reused for the life of the client.
+func (c *Client) FetchProfile(ctx context.Context, username string) (Profile, error) {
+	key := strings.ToLower(username)
+	c.mu.Lock()
+	profile, ok := c.profiles[key]
+	c.mu.Unlock()
+	if ok {
+		return profile, nil
+	}
+
+	req, err := c.newRequest(ctx, fmt.Sprintf("/users/%s", username))
+	if err != nil {
+		return profile, err
+	}
+
+	if err := c.do(req, &profile); err != nil {
+		return Profile{}, userNotFound(err, username)
+	}
+
+	c.mu.Lock()
+	if c.profiles == nil {
+		c.profiles = make(map[string]Profile)
+	}
+	c.profiles[key] = profile
+	c.mu.Unlock()
+	return profile, nil
+}
+
+// HasCustomAvatar reports whether the avatar at avatarURL is a picture the
+// user uploaded. An empty URL and identicons are recognized without a
+// request, otherwise the avatar is probed and a 404 means there is none.
+// Gravatar is asked for a 404 rather than its generated fallback.
+func (c *Client) HasCustomAvatar(ctx context.Context, avatarURL string) (bool, error) {
+	u, err := url.Parse(avatarURL)
+	if err != nil {
+		return false, fmt.Errorf("invalid avatar URL %q: %v", avatarURL, err)
+	}
+	if avatarURL == "" || strings.Contains(u.Path, "/identicons/") {
+		return false, nil
+	}
+	if strings.HasSuffix(u.Host, "gravatar.com") {
+		q := u.Query()
+		q.Set("d", "404")
+		u.RawQuery = q.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "HEAD", u.String(), nil)
+	if err != nil {
+		return false, fmt.Errorf("error creating the request: %v", err)
+	}
+	req.Header.Set("User-Agent", c.UserAgent)
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("request http error: %w", err)
+	}
+	resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == 404:
+		return false, nil
+	case resp.StatusCode >= 400:
+		return false, fmt.Errorf("http error %d", resp.StatusCode)
+	}
+	return true, nil
+}
+
+// FetchUserOrgs returns the organizations a user is a public member of.
+// Private memberships are never listed, even with a token.
+func (c *Client) FetchUserOrgs(ctx context.Context, username string) ([]Org, error) {
+	req, err := c.newRequest(ctx, fmt.Sprintf("/users/%s/orgs?per_page=100", username))
+	if err != nil {
+		return nil, err
+	}
+
+	var orgs []Org
+	if err := c.do(req, &orgs); err != nil {
+		return nil, userNotFound(err, username)
+	}
+	return orgs, nil
+}
+
+// FetchRepoLanguages returns the number of bytes written in each language
+// for a repository given as "owner/name"
+func (c *Client) FetchRepoLanguages(ctx context.Context, fullName string) (map[string]int, error) {
+	req, err := c.newRequest(ctx, fmt.Sprintf("/repos/%s/languages", fullName))
+	if err != nil {
+		return nil, err
+	}
+
+	var languages map[string]int
+	if err := c.do(req, &languages); err != nil {
+		return nil, notFound(err, "repository '%s'", fullName)
+	}
+	return languages, nil
+}
+
+// HasFile reports whether path exists, as a file or a directory, on the
+// default branch of a repository given as "owner/name"
+func (c *Client) HasFile(ctx context.Context, fullName, path string) (bool, error) {
+	// escaped segment by segment, the slashes between them are kept
+	segments := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+
+	req, err := c.newRequest(ctx, fmt.Sprintf("/repos/%s/contents/%s", fullName, strings.Join(segments, "/")))
+	if err != nil {
+		return false, err
+	}
+
+	// only the status matters, a directory gives a list instead of an object
+	var content json.RawMessage
+	if err := c.do(req, &content); errors.Is(err, ErrNotFound) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// FetchOpenCounts returns the number of open pull requests and open
+// issues authored by a user, using the search API
+func (c *Client) FetchOpenCounts(ctx context.Context, username string) (prs, issues int, err error) {
+	if prs, err = c.searchCount(ctx, fmt.Sprintf("author:%s is:open is:pr", username)); err != nil {
+		return 0, 0, err
+	}
+	if issues, err = c.searchCount(ctx, fmt.Sprintf("author:%s is:open is:issue", username)); err != nil {
+		return 0, 0, err
+	}
+	return prs, issues, nil
+}
+
+// SearchRepos returns the first repositories matching a search query,
+// such as "topic:go", most starred first
+func (c *Client) SearchRepos(ctx context.Context, query string, perPage int) ([]Repository, error) {
+	req, err := c.newRequest(ctx, fmt.Sprintf("/search/repositories?sort=stars&per_page=%d&q=%s", perPage, url.QueryEscape(query)))
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Items []Repository `json:"items"`
+	}
+	if err := c.do(req, &result); err != nil {
+		return nil, err
+	}
+	return result.Items, nil
+}
+
+// searchCount returns the total_count of an issue search
+func (c *Client) searchCount(ctx context.Context, query string) (int, error) {
+	req, err := c.newRequest(ctx, "/search/issues?per_page=1&q="+url.QueryEscape(query))
+	if err != nil {
+		return 0, err
+	}
+
+	var result struct {
+		TotalCount int `json:"total_count"`
+	}
+	if err := c.do(req, &result); err != nil {
+		return 0, err
+	}
+	return result.TotalCount, nil
+}
+
+// RateLimit returns the core rate limit for the client's token
+func (c *Client) RateLimit(ctx context.Context) (Rate, error) {
+	req, err := c.newRequest(ctx, "/rate_limit")
+	if err != nil {
+		return Rate{}, err
+	}
+
+	var rateLimit struct {
+		Resources struct {
+			Core struct {
+				Limit     int   `json:"limit"`
+				Remaining int   `json:"remaining"`
+				Reset     int64 `json:"reset"`
+			} `json:"core"`
+		} `json:"resources"`
+	}
+	if err := c.do(req, &rateLimit); err != nil {
+		return Rate{}, err
+	}
+
+	core := rateLimit.Resources.Core
+	return Rate{
+		Limit:     core.Limit,
+		Remaining: core.Remaining,
+		Reset:     time.Unix(core.Reset, 0),
+	}, nil
+}
+
+// fundingURLs turns the account name given for a FUNDING.yml platform
+// into its sponsorship page
+var fundingURLs = map[string]string{
+	"github":          "https://github.com/sponsors/%s",
+	"patreon":         "https://www.patreon.com/%s",
+	"open_collective": "https://opencollective.com/%s",
+	"ko_fi":           "https://ko-fi.com/%s",
+	"liberapay":       "https://liberapay.com/%s",
+	"issuehunt":       "https://issuehunt.io/r/%s",
+	"polar":           "https://polar.sh/%s",
+	"buy_me_a_coffee": "https://buymeacoffee.com/%s",
+	"tidelift":        "https://tidelift.com/funding/github/%s",
+}
+
+// FetchFunding returns the sponsorship links of a repository's
+// .github/FUNDING.yml. A repository without one has no links and no error.
+func (c *Client) FetchFunding(ctx context.Context, fullName string) ([]FundingLink, error) {
+	req, err := c.newRequest(ctx, fmt.Sprintf("/repos/%s/contents/.github/FUNDING.yml", fullName))
+	if err != nil {
+		return nil, err
+	}
+
+	var file struct {
+		Content  string `json:"content"`
+		Encoding string `json:"encoding"`
+	}
+	if err := c.do(req, &file); errors.Is(err, ErrNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if file.Encoding != "base64" {
+		return nil, fmt.Errorf("unexpected FUNDING.yml encoding %q", file.Encoding)
+	}
+	data, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(file.Content, "\n", ""))
+	if err != nil {
+		return nil, fmt.Errorf("error decoding FUNDING.yml: %v", err)
+	}
+	return parseFunding(string(data)), nil
+}
+
+// parseFunding reads the flat mapping of a FUNDING.yml, where each value is
+// a name, a list of names ([a, b] or "- a" lines) or, for custom, URLs.
+// It is not a general YAML parser.
+func parseFunding(data string) []FundingLink {
+	var links []FundingLink
+	add := func(platform, value string) {
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+		if value == "" || value == "~" || value == "null" {
+			return
+		}
+		url := value
+		if format, ok := fundingURLs[platform]; ok {
+			url = fmt.Sprintf(format, value)
+		} else if platform != "custom" {
+			return
+		}
+		links = append(links, FundingLink{Platform: platform, URL: url})
+	}
+
+	platform := ""
+	for _, line := range strings.Split(data, "\n") {
+		if i := strings.Index(line, " #"); i >= 0 {
+			line = line[:i]
+		}
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		if item, ok := strings.CutPrefix(trimmed, "- "); ok {
+			add(platform, item)
+			continue
+		}
+		key, value, ok := strings.Cut(trimmed, ":")
+		if !ok {
+			continue
+		}
+		platform = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+		if list, ok := strings.CutPrefix(value, "["); ok {
+			for _, item := range strings.Split(strings.TrimSuffix(list, "]"), ",") {
+				add(platform, item)
+			}
+			continue
+		}
+		add(platform, value)
+	}
+	return links
+}
@@ -0,0 +1,303 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// newTestClient returns a client talking to a test server run by handler
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	c := NewClient("")
+	c.BaseURL = srv.URL
+	return c
+}
+
+func TestCancelAbortsRequest(t *testing.T) {
+	// the server never answers on its own, only the cancellation ends the request
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	start := time.Now()
+	_, err := c.FetchActivity(ctx, "octocat", true)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("request took %v after the cancellation", elapsed)
+	}
+}
+
+// repoPages serves total repositories 100 a page, with the Link header
+// GitHub sends when another page follows
+func repoPages(t *testing.T, total int) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		var repos []Repository
+		for i := (page - 1) * 100; i < min(page*100, total); i++ {
+			repos = append(repos, Repository{Name: fmt.Sprintf("repo-%d", i)})
+		}
+		if page*100 < total {
+			w.Header().Set("Link", fmt.Sprintf(`<%s?page=%d>; rel="next", <%s?page=9>; rel="last"`, r.URL.Path, page+1, r.URL.Path))
+		}
+		if repos == nil {
+			repos = []Repository{}
+		}
+		if err := json.NewEncoder(w).Encode(repos); err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestFetchReposPageLimit(t *testing.T) {
+	tests := []struct {
+		name    string
+		total   int
+		wantLen int
+		wantErr error
+	}{
+		{"under the limit", 150, 150, nil},
+		{"exactly the limit", 200, 200, nil},
+		{"over the limit", 201, 200, ErrPageLimit},
+	}
+	for _, tt := range tests {
+		c := newTestClient(t, repoPages(t, tt.total))
+		c.MaxPages = 2
+
+		repos, err := c.FetchRepos(context.Background(), "octocat")
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: err = %v, want %v", tt.name, err, tt.wantErr)
+		}
+		if len(repos) != tt.wantLen {
+			t.Errorf("%s: %d repos, want %d", tt.name, len(repos), tt.wantLen)
+		}
+	}
+}
+
+func TestHasNextPage(t *testing.T) {
+	tests := []struct {
+		link string
+		want bool
+	}{
+		{`<https://api.github.com/user/1/repos?page=2>; rel="next", <https://api.github.com/user/1/repos?page=5>; rel="last"`, true},
+		{`<https://api.github.com/user/1/repos?page=1>; rel="first", <https://api.github.com/user/1/repos?page=4>; rel="prev"`, false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		header := http.Header{}
+		if tt.link != "" {
+			header.Set("Link", tt.link)
+		}
+		if got := hasNextPage(header); got != tt.want {
+			t.Errorf("hasNextPage(%q) = %v, want %v", tt.link, got, tt.want)
+		}
+	}
+}
+
+func TestFetchReposFollowsPages(t *testing.T) {
+	var pages []string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		pages = append(pages, r.URL.Query().Get("page"))
+		repoPages(t, 250)(w, r)
+	})
+
+	repos, err := c.FetchRepos(context.Background(), "octocat")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(repos) != 250 {
+		t.Errorf("%d repos, want 250", len(repos))
+	}
+	if repos[0].Name != "repo-0" || repos[249].Name != "repo-249" {
+		t.Errorf("repos out of order: first %s, last %s", repos[0].Name, repos[249].Name)
+	}
+	// the short third page ends the listing without asking for a fourth
+	if got := fmt.Sprint(pages); got != "[1 2 3]" {
+		t.Errorf("pages requested = %s, want [1 2 3]", got)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		token  string
+		status int
+		header map[string]string
+		want   error
+	}{
+		{"missing user", "", http.StatusNotFound, nil, ErrUserNotFound},
+		{"rejected token", "bad", http.StatusUnauthorized, nil, ErrBadToken},
+		{"rate limited", "", http.StatusForbidden, map[string]string{"X-RateLimit-Remaining": "0"}, ErrRateLimited},
+		{"too many requests", "", http.StatusTooManyRequests, nil, ErrRateLimited},
+	}
+	for _, tt := range tests {
+		c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			for k, v := range tt.header {
+				w.Header().Set(k, v)
+			}
+			w.WriteHeader(tt.status)
+		})
+		c.Token = tt.token
+
+		if _, err := c.FetchRepos(context.Background(), "octocat"); !errors.Is(err, tt.want) {
+			t.Errorf("%s: err = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestForbiddenIsNotRateLimit(t *testing.T) {
+	// a 403 with requests left is some other refusal
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "42")
+		w.WriteHeader(http.StatusForbidden)
+	})
+
+	_, err := c.FetchRepos(context.Background(), "octocat")
+	if err == nil || errors.Is(err, ErrRateLimited) {
+		t.Errorf("err = %v, want a plain http error", err)
+	}
+}
+
+func TestUnauthorizedWithoutToken(t *testing.T) {
+	// without a token there is nothing to blame, it's a plain http error
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+
+	_, err := c.FetchRepos(context.Background(), "octocat")
+	if err == nil || errors.Is(err, ErrBadToken) {
+		t.Errorf("err = %v, want a plain http error", err)
+	}
+}
+
+func TestHasCustomAvatar(t *testing.T) {
+	var probed []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		probed = append(probed, r.URL.String())
+		if r.Method != "HEAD" {
+			t.Errorf("method = %s, want HEAD", r.Method)
+		}
+		if r.URL.Path == "/u/404" || r.URL.Query().Get("d") == "404" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	c := NewClient("")
+
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{srv.URL + "/u/1?v=4", true},
+		{srv.URL + "/u/404?v=4", false},
+		{"", false},
+		{"https://github.com/identicons/octocat.png", false},
+	}
+	for _, tt := range tests {
+		got, err := c.HasCustomAvatar(context.Background(), tt.url)
+		if err != nil || got != tt.want {
+			t.Errorf("HasCustomAvatar(%q) = %v, %v, want %v", tt.url, got, err, tt.want)
+		}
+	}
+	// identicons and empty URLs are known without a request
+	if len(probed) != 2 {
+		t.Errorf("probed %v, want only the two avatar URLs", probed)
+	}
+}
+
+func TestHasFileEscapesPath(t *testing.T) {
+	var got string
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		got = r.URL.EscapedPath()
+		if r.URL.RawQuery != "" {
+			t.Errorf("path leaked into the query: %q", r.URL.RawQuery)
+		}
+		w.Write([]byte("{}"))
+	})
+
+	tests := []struct {
+		path, want string
+	}{
+		{"go.mod", "/repos/o/r/contents/go.mod"},
+		{"/.github/workflows", "/repos/o/r/contents/.github/workflows"},
+		{"docs/read me.md", "/repos/o/r/contents/docs/read%20me.md"},
+		{"what?#.txt", "/repos/o/r/contents/what%3F%23.txt"},
+		{"100%.md", "/repos/o/r/contents/100%25.md"},
+	}
+	for _, tt := range tests {
+		ok, err := c.HasFile(context.Background(), "o/r", tt.path)
+		if err != nil || !ok {
+			t.Errorf("HasFile(%q) = %v, %v", tt.path, ok, err)
+		}
+		if got != tt.want {
+			t.Errorf("HasFile(%q) requested %s, want %s", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestWithToken(t *testing.T) {
+	c := NewClient("")
+	c.BaseURL = "http://127.0.0.1:1"
+	c.UserAgent = "test-agent"
+	c.MaxPages = 3
+
+	n := c.WithToken("secret")
+	if n == c {
+		t.Fatal("the client was changed in place")
+	}
+	if c.Token != "" {
+		t.Errorf("original token = %q, want it untouched", c.Token)
+	}
+	if n.Token != "secret" || n.BaseURL != c.BaseURL || n.UserAgent != c.UserAgent ||
+		n.HTTPClient != c.HTTPClient || n.MaxPages != c.MaxPages {
+		t.Errorf("copy = %+v, want the settings of %+v with the token", n, c)
+	}
+}
+
+func TestRequestHeaders(t *testing.T) {
+	var headers []http.Header
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		headers = append(headers, r.Header.Clone())
+		w.Write([]byte("[]"))
+	})
+	c.Token = "secret"
+
+	if _, err := c.FetchActivity(context.Background(), "octocat", false); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.FetchRepos(context.Background(), "octocat"); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(headers) != 2 {
+		t.Fatalf("%d requests, want events and repos", len(headers))
+	}
+	for i, h := range headers {
+		if got := h.Get("Accept"); got != "application/vnd.github.v3+json" {
+			t.Errorf("request %d: Accept = %q", i, got)
+		}
+		if got := h.Get("X-GitHub-Api-Version"); got != "2022-11-28" {
+			t.Errorf("request %d: X-GitHub-Api-Version = %q", i, got)
+		}
+		if got := h.Get("Authorization"); got != "token secret" {
+			t.Errorf("request %d: Authorization = %q", i, got)
+		}
+		if got := h.Get("User-Agent"); got != c.UserAgent {
+			t.Errorf("request %d: User-Agent = %q", i, got)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package github
+
+import "time"
+
+// Event is an entry of a user's activity feed
+type Event struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	Actor     Actor     `json:"actor"`
+	Repo      Repo      `json:"repo"`
+	Payload   Payload   `json:"payload"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type Actor struct {
+	Login string `json:"login"`
+}
+
+// Repo is the repository an event refers to, as named at event time
+type Repo struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+type Payload struct {
+	Action      string       `json:"action,omitempty"`
+	RefType     string       `json:"ref_type,omitempty"`
+	Ref         string       `json:"ref,omitempty"`
+	Commits     []Commit     `json:"commits,omitempty"`
+	Issue       *Issue       `json:"issue,omitempty"`
+	PullRequest *PullRequest `json:"pull_request,omitempty"`
+	Release     *Release     `json:"release,omitempty"`
+}
+
+type Commit struct {
+	SHA     string `json:"sha"`
+	Message string `json:"message"`
+}
+
+type Issue struct {
+	Title string `json:"title"`
+	State string `json:"state"`
+	// StateReason is "completed" or "not_planned" for closed issues
+	StateReason string `json:"state_reason,omitempty"`
+}
+
+// Release is the release a ReleaseEvent published
+type Release struct {
+	TagName string `json:"tag_name"`
+	Name    string `json:"name"`
+	URL     string `json:"html_url"`
+}
+
+type PullRequest struct {
+	Title  string `json:"title"`
+	State  string `json:"state"`
+	Merged bool   `json:"merged"`
+}
+
+// Repository is a repository owned by a user
+type Repository struct {
+	Name        string `json:"name"`
+	FullName    string `json:"full_name"`
+	Description string `json:"description"`
+	URL         string `json:"html_url"`
+	CloneURL    string `json:"clone_url"`
+	// Homepage is the website set on the repository, often docs or a
+	// demo, empty when there is none
+	Homepage string `json:"homepage"`
+	Stars    int    `json:"stargazers_count"`
+	Forks    int    `json:"forks_count"`
+	// OpenIssues counts open issues and pull requests together
+	OpenIssues    int       `json:"open_issues_count"`
+	Language      string    `json:"language"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+	Private       bool      `json:"private"`
+	Fork          bool      `json:"fork"`
+	Topics        []string  `json:"topics"`
+	DefaultBranch string    `json:"default_branch"`
+}
+
+// Org is an organization a user is a public member of
+type Org struct {
+	Login       string `json:"login"`
+	Description string `json:"description"`
+	AvatarURL   string `json:"avatar_url"`
+}
+
+// WebURL returns the organization's page on github.com
+func (o Org) WebURL() string {
+	return "https://github.com/" + o.Login
+}
+
+// Profile is the public profile of a user or organization
+type Profile struct {
+	Login       string    `json:"login"`
+	Name        string    `json:"name"`
+	Type        string    `json:"type"`
+	Bio         string    `json:"bio"`
+	URL         string    `json:"html_url"`
+	AvatarURL   string    `json:"avatar_url"`
+	PublicRepos int       `json:"public_repos"`
+	Followers   int       `json:"followers"`
+	Following   int       `json:"following"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// Rate is the core API rate limit of the current token (or IP)
+type Rate struct {
+	Limit     int
+	Remaining int
+	Reset     time.Time
+}
+
+// FundingLink is a sponsorship link from a repository's FUNDING.yml
+type FundingLink struct {
+	Platform string
+	URL      string
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+
+	"gitact/pkg/github"
+)
+
+// plainListSize caps each list of the plain dashboard
+const plainListSize = 5
+
+// plainEventCount is the number of recent events the plain dashboard shows
+const plainEventCount = 10
+
+// showPlain runs --plain, printing a static version of the dashboard
+// for terminals where the TUI can't run
+func showPlain(client *github.Client, username string, cfg Config) {
+	ctx := context.Background()
+
+	events, err := client.FetchActivity(ctx, username, cfg.PublicOnly)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error fetching activity: %v\n", err)
+		os.Exit(exitCode(err))
+	}
+	repos, err := fetchRepos(client, username)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error fetching public repositories: %v\n", err)
+		os.Exit(exitCode(err))
+	}
+
+	// stats cover every fetched event unless asked to follow the limit,
+	// as in the dashboard
+	limited := feedEvents(events, cfg)
+	stats := calculateStats(events)
+	if cfg.LimitStats {
+		stats = calculateStats(limited)
+	}
+	// the warning for an unknown timezone was already printed at startup
+	loc, _ := cfg.location()
+	for i := range limited {
+		limited[i].CreatedAt = limited[i].CreatedAt.In(loc)
+	}
+
+	writePlainDashboard(os.Stdout, username, repos, limited, stats, terminalWidth())
+}
+
+// writePlainDashboard writes the header, top repositories, languages,
+// activity grade and recent events as text without styling. Lines are
+// cut to width, 0 for no limit.
+func writePlainDashboard(w io.Writer, username string, repos []PublicRepo, events []GitHubEvent, stats GitHubStats, width int) {
+	fmt.Fprintf(w, "GitHub Dashboard - %s\n", username)
+
+	totalStars, totalForks, forkedRepos := 0, 0, 0
+	languageCount := make(map[string]int)
+	for _, repo := range repos {
+		totalStars += repo.Stars
+		totalForks += repo.Forks
+		if repo.Fork {
+			forkedRepos++
+		}
+		if repo.Language != "" {
+			languageCount[repo.Language]++
+		}
+	}
+	fmt.Fprintf(w, "%d sources, %d forked repos | %s stars | %s forks received\n",
+		len(repos)-forkedRepos, forkedRepos, formatNumber(totalStars), formatNumber(totalForks))
+
+	fmt.Fprintf(w, "\nActivity Grade: %s (%d events)\n", getGrade(stats), stats.TotalEvents)
+
+	fmt.Fprintf(w, "\nTop Repositories by Stars:\n")
+	if len(repos) == 0 {
+		fmt.Fprintf(w, "   No public repositories\n")
+	}
+	byStars := append([]PublicRepo(nil), repos...)
+	sort.SliceStable(byStars, func(i, j int) bool { return byStars[i].Stars > byStars[j].Stars })
+	for i, repo := range byStars[:min(len(byStars), plainListSize)] {
+		fmt.Fprintln(w, truncate(fmt.Sprintf("   %d. %s - %s stars", i+1, repo.Name, formatNumber(repo.Stars)), width))
+	}
+
+	if len(languageCount) > 0 {
+		fmt.Fprintf(w, "\nProgramming Languages:\n")
+		for _, lang := range sortedByCount(languageCount) {
+			fmt.Fprintf(w, "   %s: %d repositories\n", lang, languageCount[lang])
+		}
+	}
+
+	if busiest := getTopRepos(events); len(busiest) > 0 {
+		fmt.Fprintf(w, "\nBusiest Repositories:\n")
+		for i, repo := range busiest[:min(len(busiest), plainListSize)] {
+			fmt.Fprintln(w, truncate(fmt.Sprintf("   %d. %s - %d events", i+1, repo.Name, repo.Count), width))
+		}
+	}
+
+	fmt.Fprintf(w, "\nRecent Activity:\n")
+	if len(events) == 0 {
+		fmt.Fprintf(w, "   No recent activity\n")
+	}
+	for _, event := range events[:min(len(events), plainEventCount)] {
+		fmt.Fprintln(w, truncate(fmt.Sprintf("   %s  %s", formatDateTime(event.CreatedAt), formatEventShort(event, username)), width))
+	}
+}
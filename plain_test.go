@@ -0,0 +1,41 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWritePlainDashboard(t *testing.T) {
+	base := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	repos := []PublicRepo{
+		{Name: "cli", Language: "Go", Stars: 1520, Forks: 88},
+		{Name: "site", Language: "TypeScript", Stars: 230, Forks: 12},
+		{Name: "fork", Language: "Go", Stars: 1, Fork: true},
+	}
+	events := []GitHubEvent{
+		event("PushEvent", "octocat/cli", base),
+		event("WatchEvent", "charmbracelet/bubbletea", base.Add(-time.Hour)),
+		event("PushEvent", "octocat/cli", base.Add(-2*time.Hour)),
+	}
+
+	var b strings.Builder
+	writePlainDashboard(&b, "octocat", repos, events, calculateStats(events), 0)
+	golden(t, "plain_dashboard", b.String())
+}
+
+func TestWritePlainDashboardTotals(t *testing.T) {
+	// forked repos are the user's forks, forks received are other people's
+	repos := []PublicRepo{
+		{Name: "a", Forks: 7},
+		{Name: "b", Forks: 3},
+		{Name: "c", Fork: true},
+	}
+	var b strings.Builder
+	writePlainDashboard(&b, "octocat", repos, nil, GitHubStats{}, 0)
+
+	want := "2 sources, 1 forked repos | 0 stars | 10 forks received"
+	if got := strings.Split(b.String(), "\n")[1]; got != want {
+		t.Errorf("totals line = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// repoQuery is a parsed search box query such as "lang:go stars:>100 react".
+// Every part must match for a repository to be kept.
+type repoQuery struct {
+	terms    []string // free text, matched against name, description and topics
+	lang     string
+	minStars int // exclusive, -1 when unset
+	maxStars int // exclusive, -1 when unset
+	fork     *bool
+}
+
+// parseRepoQuery parses the search box syntax:
+//
+//	lang:go       primary language, case insensitive
+//	stars:>100    more than 100 stars (stars:<100 for fewer, stars:>1.5k works)
+//	fork:true     only forks (fork:false for sources)
+//	anything else free text
+func parseRepoQuery(raw string) (repoQuery, error) {
+	q := repoQuery{minStars: -1, maxStars: -1}
+
+	for _, token := range strings.Fields(raw) {
+		name, value, ok := strings.Cut(token, ":")
+		if !ok {
+			q.terms = append(q.terms, strings.ToLower(token))
+			continue
+		}
+
+		switch strings.ToLower(name) {
+		case "lang":
+			if value == "" {
+				return q, fmt.Errorf("lang: needs a language, e.g. lang:go")
+			}
+			q.lang = strings.ToLower(value)
+
+		case "stars":
+			if len(value) < 2 || (value[0] != '>' && value[0] != '<') {
+				return q, fmt.Errorf("stars: needs > or < and a number, e.g. stars:>100")
+			}
+			n, err := parseFormattedNumber(value[1:])
+			if err != nil {
+				return q, fmt.Errorf("invalid star count %q", value[1:])
+			}
+			if value[0] == '>' {
+				q.minStars = n
+			} else {
+				q.maxStars = n
+			}
+
+		case "fork":
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return q, fmt.Errorf("fork: needs true or false")
+			}
+			q.fork = &b
+
+		default:
+			// not a known field, e.g. a URL or "c++:", search it as text
+			q.terms = append(q.terms, strings.ToLower(token))
+		}
+	}
+	return q, nil
+}
+
+// matches reports whether repo satisfies every part of the query
+func (q repoQuery) matches(repo PublicRepo) bool {
+	if q.lang != "" && strings.ToLower(repo.Language) != q.lang {
+		return false
+	}
+	if q.minStars >= 0 && repo.Stars <= q.minStars {
+		return false
+	}
+	if q.maxStars >= 0 && repo.Stars >= q.maxStars {
+		return false
+	}
+	if q.fork != nil && repo.Fork != *q.fork {
+		return false
+	}
+
+	for _, term := range q.terms {
+		if !repoContains(repo, term) {
+			return false
+		}
+	}
+	return true
+}
+
+// repoContains looks for a lowercase term in the repo name, description and topics
+func repoContains(repo PublicRepo, term string) bool {
+	if strings.Contains(strings.ToLower(repo.Name), term) ||
+		strings.Contains(strings.ToLower(repo.Description), term) {
+		return true
+	}
+	for _, topic := range repo.Topics {
+		if strings.Contains(strings.ToLower(topic), term) {
+			return true
+		}
+	}
+	return false
+}
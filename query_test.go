@@ -0,0 +1,55 @@
+package main
+
+import "testing"
+
+func TestParseRepoQueryMatches(t *testing.T) {
+	repos := map[string]PublicRepo{
+		"cli":   {Name: "cli", Language: "Go", Stars: 1500, Description: "A terminal tool"},
+		"web":   {Name: "web", Language: "TypeScript", Stars: 40, Topics: []string{"react"}},
+		"fork":  {Name: "fork", Language: "Go", Stars: 3, Fork: true},
+		"notes": {Name: "notes", Stars: 100},
+	}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"", []string{"cli", "web", "fork", "notes"}},
+		{"lang:go", []string{"cli", "fork"}},
+		{"LANG:GO", []string{"cli", "fork"}},
+		{"stars:>100", []string{"cli"}},
+		{"stars:<100", []string{"web", "fork"}},
+		{"stars:>1.4k", []string{"cli"}},
+		{"fork:true", []string{"fork"}},
+		{"fork:false lang:go", []string{"cli"}},
+		{"react", []string{"web"}},
+		{"Terminal", []string{"cli"}},
+		{"lang:go terminal", []string{"cli"}},
+		// unknown fields are searched as text
+		{"c++:", nil},
+	}
+	for _, tt := range tests {
+		q, err := parseRepoQuery(tt.query)
+		if err != nil {
+			t.Errorf("%q: %v", tt.query, err)
+			continue
+		}
+		want := make(map[string]bool)
+		for _, name := range tt.want {
+			want[name] = true
+		}
+		for name, repo := range repos {
+			if got := q.matches(repo); got != want[name] {
+				t.Errorf("%q matches %s = %v, want %v", tt.query, name, got, want[name])
+			}
+		}
+	}
+}
+
+func TestParseRepoQueryErrors(t *testing.T) {
+	for _, query := range []string{"lang:", "stars:100", "stars:>", "stars:>lots", "fork:maybe"} {
+		if _, err := parseRepoQuery(query); err == nil {
+			t.Errorf("%q: no error", query)
+		}
+	}
+}
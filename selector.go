@@ -0,0 +1,192 @@
+package main
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"gitact/pkg/github"
+)
+
+// userItem is an entry of the user selector
+type userItem struct {
+	username string
+	opened   bool
+}
+
+func (i userItem) FilterValue() string { return i.username }
+func (i userItem) Title() string       { return i.username }
+func (i userItem) Description() string {
+	if i.opened {
+		return "Dashboard loaded"
+	}
+	return "Press enter to open"
+}
+
+// userMsg carries a message produced by one user's dashboard, so it
+// reaches that dashboard even when another one is on screen
+type userMsg struct {
+	username string
+	msg      tea.Msg
+}
+
+// tagCmd wraps cmd so that its messages come back as userMsg
+func tagCmd(username string, cmd tea.Cmd) tea.Cmd {
+	if cmd == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		msg := cmd()
+		switch msg := msg.(type) {
+		case nil:
+			return nil
+		case tea.QuitMsg:
+			// quitting a dashboard quits the whole program
+			return msg
+		case tea.BatchMsg:
+			// the program runs batched commands itself, tag each of them
+			for i := range msg {
+				msg[i] = tagCmd(username, msg[i])
+			}
+			return msg
+		}
+		return userMsg{username: username, msg: msg}
+	}
+}
+
+// SelectorModel lists several users and drills into the dashboard of the
+// selected one. Dashboards are created the first time they are opened and
+// kept, so going back and forth doesn't reload them.
+type SelectorModel struct {
+	client     *github.Client
+	cfg        Config
+	list       list.Model
+	dashboards map[string]Model
+	active     string // user on screen, empty while selecting
+	width      int
+	height     int
+}
+
+// NewSelectorModel builds the selector for the given usernames
+func NewSelectorModel(client *github.Client, usernames []string, cfg Config) SelectorModel {
+	items := make([]list.Item, len(usernames))
+	for i, u := range usernames {
+		items[i] = userItem{username: u}
+	}
+
+	delegate := list.NewDefaultDelegate()
+	delegate.Styles.SelectedTitle = delegate.Styles.SelectedTitle.
+		Background(uiAccentBg).
+		Foreground(uiAccentFg).
+		Padding(0, 1)
+	delegate.Styles.SelectedDesc = delegate.Styles.SelectedDesc.
+		Background(uiAccentBg).
+		Foreground(uiAccentDesc).
+		Padding(0, 1)
+
+	l := list.New(items, delegate, 0, 0)
+	l.Title = "Select a user"
+	l.SetShowStatusBar(false)
+	l.SetFilteringEnabled(false)
+	l.Styles.Title = lipgloss.NewStyle().
+		Foreground(uiListTitle).
+		Bold(true).
+		Padding(0, 2)
+	l.AdditionalShortHelpKeys = func() []key.Binding {
+		return []key.Binding{keys.Enter, keys.Back}
+	}
+
+	return SelectorModel{
+		client:     client,
+		cfg:        cfg,
+		list:       l,
+		dashboards: make(map[string]Model),
+	}
+}
+
+func (m SelectorModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m SelectorModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.list.SetSize(msg.Width-4, msg.Height-2)
+		if m.active != "" {
+			return m.updateDashboard(m.active, msg)
+		}
+		return m, nil
+
+	case userMsg:
+		if _, ok := m.dashboards[msg.username]; !ok {
+			return m, nil
+		}
+		return m.updateDashboard(msg.username, msg.msg)
+
+	case tea.KeyMsg:
+		if m.active != "" {
+			if key.Matches(msg, keys.Back) && !m.dashboards[m.active].typing() {
+				m.active = ""
+				return m, nil
+			}
+			return m.updateDashboard(m.active, msg)
+		}
+
+		switch {
+		case key.Matches(msg, keys.Quit):
+			for _, d := range m.dashboards {
+				d.cancelLoad()
+			}
+			return m, tea.Quit
+		case key.Matches(msg, keys.Enter):
+			if item, ok := m.list.SelectedItem().(userItem); ok {
+				return m.open(item)
+			}
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.list, cmd = m.list.Update(msg)
+	return m, cmd
+}
+
+// open shows the dashboard of the selected user, creating it on first use
+func (m SelectorModel) open(item userItem) (tea.Model, tea.Cmd) {
+	m.active = item.username
+	if _, ok := m.dashboards[item.username]; ok {
+		// the size may have changed while the dashboard was hidden
+		return m.updateDashboard(item.username, tea.WindowSizeMsg{Width: m.width, Height: m.height})
+	}
+
+	item.opened = true
+	m.list.SetItem(m.list.Index(), item)
+
+	d := NewModel(m.client, item.username, m.cfg)
+	m.dashboards[item.username] = d
+	next, sizeCmd := m.updateDashboard(item.username, tea.WindowSizeMsg{Width: m.width, Height: m.height})
+	return next, tea.Batch(tagCmd(item.username, d.Init()), sizeCmd)
+}
+
+// updateDashboard forwards msg to one user's dashboard
+func (m SelectorModel) updateDashboard(username string, msg tea.Msg) (tea.Model, tea.Cmd) {
+	updated, cmd := m.dashboards[username].Update(msg)
+	switch d := updated.(type) {
+	case Model:
+		m.dashboards[username] = d
+	case *Model:
+		// the input handlers hand back a pointer
+		m.dashboards[username] = *d
+	}
+	return m, tagCmd(username, cmd)
+}
+
+func (m SelectorModel) View() string {
+	if m.active != "" {
+		return m.dashboards[m.active].View()
+	}
+	return lipgloss.NewStyle().Padding(1, 2).Render(m.list.View())
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// lastSeenPath returns the file remembering the newest event seen of each
+// user, e.g. ~/.cache/gitact/last_seen.json on Linux
+func lastSeenPath() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "gitact", "last_seen.json"), nil
+}
+
+// readLastSeen reads the newest event seen of each user, keyed by
+// lowercase login. A missing file gives an empty map.
+func readLastSeen() (map[string]time.Time, error) {
+	seen := make(map[string]time.Time)
+
+	path, err := lastSeenPath()
+	if err != nil {
+		return seen, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return seen, nil
+	} else if err != nil {
+		return seen, err
+	}
+	if err := json.Unmarshal(data, &seen); err != nil {
+		return make(map[string]time.Time), fmt.Errorf("error parsing %s: %v", path, err)
+	}
+	return seen, nil
+}
+
+// lastSeen returns the newest event seen of username in the last session,
+// the zero time when the user was never visited
+func lastSeen(username string) time.Time {
+	seen, _ := readLastSeen()
+	return seen[strings.ToLower(username)]
+}
+
+// saveLastSeen records t as the newest event seen of username, unless a
+// newer one is already recorded
+func saveLastSeen(username string, t time.Time) error {
+	// a file that can't be parsed is replaced
+	seen, _ := readLastSeen()
+	login := strings.ToLower(username)
+	if !t.After(seen[login]) {
+		return nil
+	}
+	seen[login] = t
+
+	path, err := lastSeenPath()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(seen, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
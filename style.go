@@ -1,7 +1,11 @@
 package main
 
 import (
+	"fmt"
+	"regexp"
+
 	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
 )
 
 var (
@@ -31,83 +35,218 @@ var (
 	nvimBorderFocus = lipgloss.Color("#7aa2f7")
 )
 
-// interface style
+// dashboard colors (256-color palette)
 var (
+	uiAccentBg   = lipgloss.Color("57")  // header and selection
+	uiAccentFg   = lipgloss.Color("230") // text on accent
+	uiAccentDesc = lipgloss.Color("254") // secondary text on accent
+	uiRule       = lipgloss.Color("240") // table header rule
+	uiFrame      = lipgloss.Color("62")  // box borders
+	uiSuccessBg  = lipgloss.Color("28")
+	uiErrorBg    = lipgloss.Color("124")
+	uiNotifFg    = lipgloss.Color("15")
+	uiSearchBg   = lipgloss.Color("235")
+	uiMuted      = lipgloss.Color("241")
+	uiHelpDesc   = lipgloss.Color("243")
+	uiHelpSep    = lipgloss.Color("237")
+	uiListTitle  = lipgloss.Color("86")
+	uiSpinner    = lipgloss.Color("205")
+
+	// heatmap cells from no activity to the busiest days
+	uiHeat = []lipgloss.Color{"236", "22", "28", "34", "46"}
+
+	// initials avatar backgrounds, picked from the login
+	uiAvatar = []lipgloss.Color{"161", "166", "136", "29", "31", "97"}
+)
+
+// setupPalette swaps in a curated 16-color palette when the terminal only
+// supports basic ANSI colors, instead of letting lipgloss pick the nearest
+// match for each hex value. Must run before the model is built.
+func setupPalette() {
+	if lipgloss.ColorProfile() != termenv.ANSI {
+		return
+	}
+
+	nvimBg = lipgloss.Color("0")
+	nvimBgDark = lipgloss.Color("0")
+	nvimBgFloat = lipgloss.Color("8")
+	nvimBgSidebar = lipgloss.Color("0")
+	nvimFg = lipgloss.Color("15")
+	nvimFgDark = lipgloss.Color("7")
+	nvimFgDarker = lipgloss.Color("8")
+	nvimBlue = lipgloss.Color("12")
+	nvimCyan = lipgloss.Color("14")
+	nvimGreen = lipgloss.Color("10")
+	nvimYellow = lipgloss.Color("11")
+	nvimOrange = lipgloss.Color("3")
+	nvimRed = lipgloss.Color("9")
+	nvimPurple = lipgloss.Color("13")
+	nvimMagenta = lipgloss.Color("5")
+	nvimBorder = lipgloss.Color("8")
+	nvimBorderFocus = lipgloss.Color("12")
+
+	uiAccentBg = lipgloss.Color("5")
+	uiAccentFg = lipgloss.Color("15")
+	uiAccentDesc = lipgloss.Color("7")
+	uiRule = lipgloss.Color("8")
+	uiFrame = lipgloss.Color("4")
+	uiSuccessBg = lipgloss.Color("2")
+	uiErrorBg = lipgloss.Color("1")
+	uiNotifFg = lipgloss.Color("15")
+	uiSearchBg = lipgloss.Color("0")
+	uiMuted = lipgloss.Color("8")
+	uiHelpDesc = lipgloss.Color("7")
+	uiHelpSep = lipgloss.Color("8")
+	uiListTitle = lipgloss.Color("14")
+	uiSpinner = lipgloss.Color("13")
+	uiHeat = []lipgloss.Color{"8", "2", "2", "10", "10"}
+	uiAvatar = []lipgloss.Color{"1", "2", "3", "4", "5", "6"}
+
+	initStyles()
+}
+
+// hexColor matches #rgb and #rrggbb colors
+var hexColor = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// parseAccent validates an accent color given as hex, with or without #
+func parseAccent(s string) (lipgloss.Color, error) {
+	hex := s
+	if hex != "" && hex[0] != '#' {
+		hex = "#" + hex
+	}
+	if !hexColor.MatchString(hex) {
+		return "", fmt.Errorf("invalid accent color %q, expected hex like #7aa2f7", s)
+	}
+	return lipgloss.Color(hex), nil
+}
+
+// setAccent replaces the accent of the header, titles and selection.
+// Must run after setupPalette, which would reset it.
+func setAccent(accent lipgloss.Color) {
+	nvimBlue = accent
+	nvimBorderFocus = accent
+	uiAccentBg = accent
+	initStyles()
+}
+
+// interface style, built from the palette by initStyles
+var (
+	baseStyle         lipgloss.Style
+	headerBarStyle    lipgloss.Style
+	sidebarStyle      lipgloss.Style
+	mainContentStyle  lipgloss.Style
+	statusLineStyle   lipgloss.Style
+	selectedItemStyle lipgloss.Style
+	normalItemStyle   lipgloss.Style
+	successNotifStyle lipgloss.Style
+	errorNotifStyle   lipgloss.Style
+	titleStyle        lipgloss.Style
+	statLabelStyle    lipgloss.Style
+	statValueStyle    lipgloss.Style
+	helpTextStyle     lipgloss.Style
+)
+
+func init() {
+	initStyles()
+}
+
+func initStyles() {
 	// basic background style
 	baseStyle = lipgloss.NewStyle().
-			Background(nvimBg).
-			Foreground(nvimFg)
+		Background(nvimBg).
+		Foreground(nvimFg)
 
 	// tabline
 	headerBarStyle = lipgloss.NewStyle().
-			Background(nvimBgDark).
-			Foreground(nvimBlue).
-			Bold(true).
-			Padding(0, 2)
+		Background(nvimBgDark).
+		Foreground(nvimBlue).
+		Bold(true).
+		Padding(0, 2)
 
 	// sidebar
 	sidebarStyle = lipgloss.NewStyle().
-			Background(nvimBgSidebar).
-			Foreground(nvimFg).
-			Border(lipgloss.NormalBorder(), false, true, false, false).
-			BorderForeground(nvimBorder).
-			Padding(1, 1)
+		Background(nvimBgSidebar).
+		Foreground(nvimFg).
+		Border(lipgloss.NormalBorder(), false, true, false, false).
+		BorderForeground(nvimBorder).
+		Padding(1, 1)
 
 	// principal content
 	mainContentStyle = lipgloss.NewStyle().
-				Background(nvimBg).
-				Foreground(nvimFg).
-				Padding(1, 2)
+		Background(nvimBg).
+		Foreground(nvimFg).
+		Padding(1, 2)
 
 	// statusline nvim like
 	statusLineStyle = lipgloss.NewStyle().
-			Background(nvimBgDark).
-			Foreground(nvimFg).
-			Padding(0, 2)
+		Background(nvimBgDark).
+		Foreground(nvimFg).
+		Padding(0, 2)
 
 	// select elmt
 	selectedItemStyle = lipgloss.NewStyle().
-				Background(nvimBgFloat).
-				Foreground(nvimYellow).
-				Bold(true).
-				Padding(0, 1)
+		Background(nvimBgFloat).
+		Foreground(nvimYellow).
+		Bold(true).
+		Padding(0, 1)
 
 	normalItemStyle = lipgloss.NewStyle().
-			Foreground(nvimFgDark).
-			Padding(0, 1)
+		Foreground(nvimFgDark).
+		Padding(0, 1)
 
 	// notif
 	successNotifStyle = lipgloss.NewStyle().
-				Background(nvimGreen).
-				Foreground(nvimBg).
-				Bold(true).
-				Padding(0, 2)
+		Background(nvimGreen).
+		Foreground(nvimBg).
+		Bold(true).
+		Padding(0, 2)
 
 	errorNotifStyle = lipgloss.NewStyle().
-			Background(nvimRed).
-			Foreground(nvimBg).
-			Bold(true).
-			Padding(0, 2)
+		Background(nvimRed).
+		Foreground(nvimBg).
+		Bold(true).
+		Padding(0, 2)
 
 	// section title
 	titleStyle = lipgloss.NewStyle().
-			Foreground(nvimBlue).
-			Bold(true).
-			Underline(true)
+		Foreground(nvimBlue).
+		Bold(true).
+		Underline(true)
 
 	// stat
 	statLabelStyle = lipgloss.NewStyle().
-			Foreground(nvimFgDark)
+		Foreground(nvimFgDark)
 
 	statValueStyle = lipgloss.NewStyle().
-			Foreground(nvimYellow).
-			Bold(true)
+		Foreground(nvimYellow).
+		Bold(true)
 
 	// help text
 	helpTextStyle = lipgloss.NewStyle().
-			Foreground(nvimFgDarker).
-			Italic(true)
-)
+		Foreground(nvimFgDarker).
+		Italic(true)
+}
+
+// getEventActionColor colors issue and pull request events by outcome:
+// merged or completed in green, otherwise closed in red, opened in blue.
+// ok is false for events without a colored action.
+func getEventActionColor(event GitHubEvent) (color lipgloss.Color, ok bool) {
+	if event.Type != "IssuesEvent" && event.Type != "PullRequestEvent" {
+		return "", false
+	}
+	switch eventAction(event) {
+	case "Merged":
+		return nvimGreen, true
+	case "Closed":
+		if issue := event.Payload.Issue; issue != nil && issue.StateReason == "completed" {
+			return nvimGreen, true
+		}
+		return nvimRed, true
+	case "Opened", "Reopened":
+		return nvimBlue, true
+	}
+	return "", false
+}
 
 func getEventIconAndColor(eventType string) (string, lipgloss.Color) {
 	switch eventType {
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"gitact/pkg/github"
+)
+
+// profileSummary is the JSON of --summary. A section whose requests
+// failed is left out, with the reason under the same key in Errors.
+type profileSummary struct {
+	Username string            `json:"username"`
+	Profile  *github.Profile   `json:"profile,omitempty"`
+	Repos    *summaryRepos     `json:"repos,omitempty"`
+	Activity *summaryActivity  `json:"activity,omitempty"`
+	Errors   map[string]string `json:"errors,omitempty"`
+}
+
+// summaryRepos aggregates the public repositories. Incomplete is set
+// when --max-pages stopped the listing.
+type summaryRepos struct {
+	Count      int            `json:"count"`
+	Stars      int            `json:"stars"`
+	Forks      int            `json:"forks"`
+	Languages  map[string]int `json:"languages"`
+	TopRepos   []topRepo      `json:"top_repos"`
+	Incomplete bool           `json:"incomplete,omitempty"`
+}
+
+// summaryActivity is the grade of the recent events and its breakdown
+type summaryActivity struct {
+	Grade     string             `json:"grade"`
+	Score     float64            `json:"score"`
+	Events    int                `json:"events"`
+	Breakdown map[string]float64 `json:"breakdown"`
+}
+
+// buildProfileSummary fetches the profile, repositories and activity of
+// username, keeping the sections that loaded. err is the first failure
+// when none did.
+func buildProfileSummary(ctx context.Context, client *github.Client, username string, cfg Config) (profileSummary, error) {
+	s := profileSummary{Username: username, Errors: make(map[string]string)}
+	var firstErr error
+	fail := func(section string, err error) {
+		s.Errors[section] = err.Error()
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	if profile, err := client.FetchProfile(ctx, username); err != nil {
+		fail("profile", err)
+	} else {
+		s.Profile = &profile
+	}
+
+	// a page-limited list still gives meaningful aggregates
+	if repos, err := client.FetchRepos(ctx, username); err != nil && !errors.Is(err, github.ErrPageLimit) {
+		fail("repos", err)
+	} else {
+		stats := buildStatsSummary(username, repos, GitHubStats{})
+		s.Repos = &summaryRepos{
+			Count:      stats.Repos,
+			Stars:      stats.Stars,
+			Forks:      stats.Forks,
+			Languages:  stats.Languages,
+			TopRepos:   stats.TopRepos,
+			Incomplete: err != nil,
+		}
+	}
+
+	if events, err := client.FetchActivity(ctx, username, cfg.PublicOnly); err != nil {
+		fail("activity", err)
+	} else {
+		// same events as the grade of the dashboard
+		stats := calculateStats(events)
+		if cfg.LimitStats {
+			stats = calculateStats(feedEvents(events, cfg))
+		}
+		s.Activity = &summaryActivity{
+			Grade:     getGrade(stats),
+			Score:     gradeScore(stats),
+			Events:    stats.TotalEvents,
+			Breakdown: gradeBreakdown(stats),
+		}
+	}
+
+	if s.Profile == nil && s.Repos == nil && s.Activity == nil {
+		return s, firstErr
+	}
+	return s, nil
+}
+
+func writeProfileSummaryJSON(w io.Writer, s profileSummary) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(s)
+}
+
+// showSummary runs --summary --json. Failed sections are reported on
+// stderr and the command only fails when none loaded.
+func showSummary(client *github.Client, username string, cfg Config) {
+	s, err := buildProfileSummary(context.Background(), client, username, cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error fetching the summary: %v\n", err)
+		os.Exit(exitCode(err))
+	}
+	for _, section := range []string{"profile", "repos", "activity"} {
+		if msg, ok := s.Errors[section]; ok {
+			fmt.Fprintf(os.Stderr, "warning: %s left out: %s\n", section, msg)
+		}
+	}
+	if err := writeProfileSummaryJSON(os.Stdout, s); err != nil {
+		fmt.Fprintf(os.Stderr, "error writing output: %v\n", err)
+		os.Exit(1)
+	}
+}
@@ -1,47 +1,19 @@
 package main
 
-import "time"
+import (
+	"time"
 
-type GitHubEvent struct {
-	Type      string    `json:"type"`
-	Actor     Actor     `json:"actor"`
-	Repo      Repo      `json:"repo"`
-	Payload   Payload   `json:"payload"`
-	CreatedAt time.Time `json:"created_at"`
-}
-
-type Actor struct {
-	Login string `json:"login"`
-}
-
-type Repo struct {
-	Name string `json:"name"`
-	URL  string `json:"url"`
-}
-
-type Payload struct {
-	Action      string       `json:"action,omitempty"`
-	RefType     string       `json:"ref_type,omitempty"`
-	Ref         string       `json:"ref,omitempty"`
-	Commits     []Commit     `json:"commits,omitempty"`
-	Issue       *Issue       `json:"issue,omitempty"`
-	PullRequest *PullRequest `json:"pull_request,omitempty"`
-}
+	"gitact/pkg/github"
+)
 
-type Commit struct {
-	SHA     string `json:"sha"`
-	Message string `json:"message"`
-}
-
-type Issue struct {
-	Title string `json:"title"`
-	State string `json:"state"`
-}
-
-type PullRequest struct {
-	Title string `json:"title"`
-	State string `json:"state"`
-}
+// API types live in pkg/github, the aliases keep the dashboard code short
+type (
+	GitHubEvent = github.Event
+	Repo        = github.Repo
+	PublicRepo  = github.Repository
+	UserProfile = github.Profile
+	Org         = github.Org
+)
 
 type GitHubStats struct {
 	PushEvents        int
@@ -66,18 +38,11 @@ type RepoInfo struct {
 	Description  string
 }
 
-type PublicRepo struct {
-	Name        string    `json:"name"`
-	FullName    string    `json:"full_name"`
-	Description string    `json:"description"`
-	URL         string    `json:"html_url"`
-	CloneURL    string    `json:"clone_url"`
-	Stars       int       `json:"stargazers_count"`
-	Forks       int       `json:"forks_count"`
-	Language    string    `json:"language"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
-	Private     bool      `json:"private"`
+// collapsedEvent is an event of the feed standing for count consecutive
+// pushes to the same repo, event being the most recent of them
+type collapsedEvent struct {
+	event GitHubEvent
+	count int
 }
 
 type NotificationMsg struct {
@@ -86,3 +51,12 @@ type NotificationMsg struct {
 }
 
 type ClearNotificationMsg struct{}
+
+// watchTickMsg drives the auto-refresh countdown, once a second
+type watchTickMsg struct{}
+
+// viewFlashEndMsg ends the highlight of the view name started by the
+// view switch numbered seq
+type viewFlashEndMsg struct {
+	seq int
+}
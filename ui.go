@@ -1,9 +1,17 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"runtime"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 
@@ -18,6 +26,8 @@ import (
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+
+	"gitact/pkg/github"
 )
 
 // Key bindings
@@ -32,9 +42,25 @@ type keyMap struct {
 	Clone   key.Binding
 	Copy    key.Binding
 	Open    key.Binding
+	Link    key.Binding
+	Langs   key.Binding
+	Jump    key.Binding
 	Search  key.Binding
 	Refresh key.Binding
 	Tab     key.Binding
+	Back    key.Binding
+	Retry   key.Binding
+	Header  key.Binding
+	Sort    key.Binding
+	Export  key.Binding
+	Funding key.Binding
+	Badge   key.Binding
+	Website key.Binding
+	Mark    key.Binding
+	Compare key.Binding
+	Primary key.Binding
+	Stats   key.Binding
+	Types   key.Binding
 }
 
 func (k keyMap) ShortHelp() []key.Binding {
@@ -44,62 +70,231 @@ func (k keyMap) ShortHelp() []key.Binding {
 func (k keyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
 		{k.Up, k.Down, k.Left, k.Right, k.Help, k.Quit},
-		{k.Enter, k.Clone, k.Copy, k.Open},
-		{k.Search, k.Refresh, k.Tab},
-	}
-}
-
-var keys = keyMap{
-	Up: key.NewBinding(
-		key.WithKeys("up", "k"),
-		key.WithHelp("↑/k", "move up"),
-	),
-	Down: key.NewBinding(
-		key.WithKeys("down", "j"),
-		key.WithHelp("↓/j", "move down"),
-	),
-	Left: key.NewBinding(
-		key.WithKeys("left", "h"),
-		key.WithHelp("←/h", "previous tab"),
-	),
-	Right: key.NewBinding(
-		key.WithKeys("right", "l"),
-		key.WithHelp("→/l", "next tab"),
-	),
-	Help: key.NewBinding(
-		key.WithKeys("?"),
-		key.WithHelp("?", "toggle help"),
-	),
-	Enter: key.NewBinding(
-		key.WithKeys("enter"),
-		key.WithHelp("enter", "select"),
-	),
-	Clone: key.NewBinding(
-		key.WithKeys("c"),
-		key.WithHelp("c", "clone repo"),
-	),
-	Copy: key.NewBinding(
-		key.WithKeys("x"),
-		key.WithHelp("x", "copy URL"),
-	),
-	Open: key.NewBinding(
-		key.WithKeys("o"),
-		key.WithHelp("o", "open in browser"),
-	),
-	Search: key.NewBinding(
-		key.WithKeys("/"),
-		key.WithHelp("/", "search"),
-	),
-	Refresh: key.NewBinding(
-		key.WithKeys("r"),
-		key.WithHelp("r", "refresh"),
-	),
-	Tab: key.NewBinding(
-		key.WithKeys("tab"),
-		key.WithHelp("tab", "switch view"),
-	),
+		{k.Enter, k.Clone, k.Copy, k.Open, k.Website, k.Link, k.Badge, k.Funding, k.Jump, k.Sort},
+		{k.Search, k.Langs, k.Stats, k.Primary, k.Mark, k.Compare, k.Types, k.Refresh, k.Retry, k.Header, k.Export, k.Tab},
+	}
+}
+
+// keyActions lists the action names usable in the config file, in the
+// order they are checked for conflicts.
+var keyActions = []string{
+	"up", "down", "left", "right", "help", "quit",
+	"enter", "clone", "copy", "open", "link", "languages", "jump", "search", "refresh", "tab", "back", "retry", "header", "sort", "export", "funding", "badge", "website", "mark", "compare", "primary", "stats", "types",
+}
+
+// bindings returns the binding behind each action name
+func (k *keyMap) bindings() map[string]*key.Binding {
+	return map[string]*key.Binding{
+		"up":        &k.Up,
+		"down":      &k.Down,
+		"left":      &k.Left,
+		"right":     &k.Right,
+		"help":      &k.Help,
+		"quit":      &k.Quit,
+		"enter":     &k.Enter,
+		"clone":     &k.Clone,
+		"copy":      &k.Copy,
+		"open":      &k.Open,
+		"link":      &k.Link,
+		"languages": &k.Langs,
+		"jump":      &k.Jump,
+		"search":    &k.Search,
+		"refresh":   &k.Refresh,
+		"tab":       &k.Tab,
+		"back":      &k.Back,
+		"retry":     &k.Retry,
+		"header":    &k.Header,
+		"sort":      &k.Sort,
+		"export":    &k.Export,
+		"funding":   &k.Funding,
+		"badge":     &k.Badge,
+		"website":   &k.Website,
+		"mark":      &k.Mark,
+		"compare":   &k.Compare,
+		"primary":   &k.Primary,
+		"stats":     &k.Stats,
+		"types":     &k.Types,
+	}
 }
 
+func defaultKeyMap() keyMap {
+	return keyMap{
+		Up: key.NewBinding(
+			key.WithKeys("up", "k"),
+			key.WithHelp("↑/k", "move up"),
+		),
+		Down: key.NewBinding(
+			key.WithKeys("down", "j"),
+			key.WithHelp("↓/j", "move down"),
+		),
+		Left: key.NewBinding(
+			key.WithKeys("left", "h"),
+			key.WithHelp("←/h", "previous tab"),
+		),
+		Right: key.NewBinding(
+			key.WithKeys("right", "l"),
+			key.WithHelp("→/l", "next tab"),
+		),
+		Help: key.NewBinding(
+			key.WithKeys("?"),
+			key.WithHelp("?", "toggle help"),
+		),
+		Quit: key.NewBinding(
+			key.WithKeys("q", "esc", "ctrl+c"),
+			key.WithHelp("q/esc", "quit"),
+		),
+		Enter: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "select"),
+		),
+		Clone: key.NewBinding(
+			key.WithKeys("c"),
+			key.WithHelp("c", "clone repo"),
+		),
+		Copy: key.NewBinding(
+			key.WithKeys("x"),
+			key.WithHelp("x", "copy URL"),
+		),
+		Open: key.NewBinding(
+			key.WithKeys("o"),
+			key.WithHelp("o", "open in browser"),
+		),
+		Link: key.NewBinding(
+			key.WithKeys("m"),
+			key.WithHelp("m", "copy markdown link"),
+		),
+		Langs: key.NewBinding(
+			key.WithKeys("a"),
+			key.WithHelp("a", "analyze languages"),
+		),
+		Jump: key.NewBinding(
+			key.WithKeys("g"),
+			key.WithHelp("g", "go to repo"),
+		),
+		Search: key.NewBinding(
+			key.WithKeys("/"),
+			key.WithHelp("/", "search"),
+		),
+		Refresh: key.NewBinding(
+			key.WithKeys("r"),
+			key.WithHelp("r", "refresh"),
+		),
+		Tab: key.NewBinding(
+			key.WithKeys("tab"),
+			key.WithHelp("tab", "switch view"),
+		),
+		Back: key.NewBinding(
+			key.WithKeys("backspace"),
+			key.WithHelp("backspace", "back to users"),
+		),
+		Retry: key.NewBinding(
+			key.WithKeys("R"),
+			key.WithHelp("R", "retry failed section"),
+		),
+		Header: key.NewBinding(
+			key.WithKeys("H"),
+			key.WithHelp("H", "compact header"),
+		),
+		Sort: key.NewBinding(
+			key.WithKeys("s"),
+			key.WithHelp("s", "sort by stars/popularity"),
+		),
+		Export: key.NewBinding(
+			key.WithKeys("e"),
+			key.WithHelp("e", "export view to file"),
+		),
+		Funding: key.NewBinding(
+			key.WithKeys("f"),
+			key.WithHelp("f", "funding links"),
+		),
+		Badge: key.NewBinding(
+			key.WithKeys("b"),
+			key.WithHelp("b", "copy stars badge"),
+		),
+		Website: key.NewBinding(
+			key.WithKeys("w"),
+			key.WithHelp("w", "open homepage"),
+		),
+		Mark: key.NewBinding(
+			key.WithKeys(" "),
+			key.WithHelp("space", "mark to compare"),
+		),
+		Compare: key.NewBinding(
+			key.WithKeys("v"),
+			key.WithHelp("v", "compare marked"),
+		),
+		Primary: key.NewBinding(
+			key.WithKeys("L"),
+			key.WithHelp("L", "main language only"),
+		),
+		Stats: key.NewBinding(
+			key.WithKeys("J"),
+			key.WithHelp("J", "copy stats as JSON"),
+		),
+		Types: key.NewBinding(
+			key.WithKeys("t"),
+			key.WithHelp("t", "cycle event types"),
+		),
+	}
+}
+
+// buildKeyMap applies the overrides from the config file on top of the
+// default bindings. Invalid entries are skipped and reported as warnings,
+// along with keys bound to more than one action.
+func buildKeyMap(overrides map[string][]string) (keyMap, []string) {
+	km := defaultKeyMap()
+	bindings := km.bindings()
+	var warnings []string
+
+	for name := range overrides {
+		if _, ok := bindings[name]; !ok {
+			warnings = append(warnings, fmt.Sprintf("unknown key action %q", name))
+		}
+	}
+	sort.Strings(warnings)
+
+	for _, name := range keyActions {
+		keyList, ok := overrides[name]
+		if !ok {
+			continue
+		}
+
+		var cleaned []string
+		for _, k := range keyList {
+			if k = strings.TrimSpace(k); k != "" {
+				cleaned = append(cleaned, k)
+			}
+		}
+		if len(cleaned) == 0 {
+			warnings = append(warnings, fmt.Sprintf("action %q has no keys, keeping default", name))
+			continue
+		}
+
+		b := bindings[name]
+		*b = key.NewBinding(
+			key.WithKeys(cleaned...),
+			key.WithHelp(strings.Join(cleaned, "/"), b.Help().Desc),
+		)
+	}
+
+	// detect keys shared by several actions
+	owners := make(map[string]string)
+	for _, name := range keyActions {
+		for _, k := range bindings[name].Keys() {
+			if owner, ok := owners[k]; ok {
+				warnings = append(warnings, fmt.Sprintf("key %q is bound to both %q and %q", k, owner, name))
+				continue
+			}
+			owners[k] = name
+		}
+	}
+
+	return km, warnings
+}
+
+// keys is rebuilt from the config file at startup
+var keys = defaultKeyMap()
+
 // Views
 type viewMode int
 
@@ -108,44 +303,87 @@ const (
 	repoTableView
 	statsView
 	activityView
+	subscriptionsView
 )
 
 // List item for repositories
 type repoItem struct {
-	repo PublicRepo
+	repo   PublicRepo
+	marked bool // marked to be compared
 }
 
 func (i repoItem) FilterValue() string { return i.repo.Name }
 func (i repoItem) Title() string {
-	return fmt.Sprintf("%s ★ %s", i.repo.Name, formatNumber(i.repo.Stars))
+	title := fmt.Sprintf("%s ★ %s", i.repo.Name, formatNumber(i.repo.Stars))
+	if i.marked {
+		title = "● " + title
+	}
+	return title
 }
 func (i repoItem) Description() string {
-	desc := i.repo.Description
+	desc := sanitizeDisplay(i.repo.Description)
 	if desc == "" {
 		desc = "No description"
 	}
-	if len(desc) > 80 {
-		desc = desc[:77] + "..."
-	}
+	desc = truncate(desc, 80)
 	return fmt.Sprintf("⑂ %s • %s", formatNumber(i.repo.Forks), desc)
 }
 
 // Activity item
 type activityItem struct {
-	event GitHubEvent
+	event     GitHubEvent
+	selfLogin string
+	// count is the number of pushes merged into the item by --collapse
+	count int
+	isNew bool // newer than the newest event of the last session
 }
 
 func (i activityItem) FilterValue() string { return i.event.Repo.Name }
 func (i activityItem) Title() string {
-	return formatEventShort(i.event)
+	return formatCollapsedShort(collapsedEvent{event: i.event, count: i.count}, i.selfLogin)
 }
 func (i activityItem) Description() string {
-	return i.event.CreatedAt.Format("2006-01-02 15:04")
+	if i.isNew {
+		return formatDateTime(i.event.CreatedAt) + " • new"
+	}
+	return formatDateTime(i.event.CreatedAt)
+}
+
+// itemDelegate renders list items like the default delegate, coloring
+// activity titles by their action
+type itemDelegate struct {
+	list.DefaultDelegate
+}
+
+func (d itemDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
+	if i, ok := item.(activityItem); ok {
+		if color, ok := getEventActionColor(i.event); ok {
+			d.Styles.NormalTitle = d.Styles.NormalTitle.Foreground(color)
+		}
+		if i.isNew {
+			d.Styles.NormalTitle = d.Styles.NormalTitle.Bold(true)
+			d.Styles.NormalDesc = d.Styles.NormalDesc.Foreground(nvimYellow)
+		}
+	}
+	d.DefaultDelegate.Render(w, m, index, item)
 }
 
 // Model
 type Model struct {
-	username    string
+	client   *github.Client
+	username string
+	cfg      Config
+	loc      *time.Location
+
+	// how long a notification stays before ClearNotificationMsg
+	notifDuration time.Duration
+
+	// auto-refresh period, 0 when off, and when the next one happens.
+	// watchWarned is set once the rate limit budget has been checked.
+	interval    time.Duration
+	nextRefresh time.Time
+	watchWarned bool
+
 	events      []GitHubEvent
 	repos       []RepoInfo
 	publicRepos []PublicRepo
@@ -162,33 +400,138 @@ type Model struct {
 	paginator paginator.Model
 
 	// State
-	currentView  viewMode
-	loading      bool
-	showHelp     bool
-	searchMode   bool
-	notification string
-	notifSuccess bool
-	width        int
-	height       int
-	ready        bool
-
-	// Data loading state
+	currentView      viewMode
+	cursors          [subscriptionsView + 1]int // list index per view, restored when switching back
+	loading          bool
+	showHelp         bool
+	compactHeader    bool // header collapsed to a single line
+	comparing        bool // the marked repos are shown side by side
+	viewFlash        bool // view name highlighted after a switch
+	viewFlashSeq     int  // counts switches, so only the last one ends the highlight
+	sortByPopularity bool // repos ordered by popularityScore instead of stars
+	statsStale       bool // the stats viewport content is out of date
+	searchMode       bool
+	exportMode       bool // asking for the file the current view is exported to
+	askUser          bool // the username doesn't exist, prompting for another
+	askToken         bool // rate limited without a token, prompting for one
+	notification     string
+	notifSuccess     bool
+	dominantLang     string   // most common language of the repos
+	langOnly         bool     // the repo list only shows dominantLang
+	marked           []string // full names of the repos marked to compare, oldest first
+	width            int
+	height           int
+	lastSeen         time.Time // newest event seen in the last session, events after it are new
+	working          string    // action started by a key, shown with the spinner until its result
+	typeCycle        int       // types key: 0 shows --event-types, i the i-th of eventTypeOrder
+	initials         bool      // the user has no profile picture, the header shows their initials
+
+	// Repo shown in full by the enter key, nil otherwise
+	detail *PublicRepo
+
+	// Data loading state, cancelLoad aborts the requests made with loadCtx.
+	// reposErr and eventsErr keep the last failure of each section until
+	// it is retried.
 	reposLoaded  bool
 	eventsLoaded bool
+	reposErr     error
+	eventsErr    error
+	loadCtx      context.Context
+	cancelLoad   context.CancelFunc
+
+	// Activity pages, the next one is fetched when the list is scrolled
+	// near its end. fetchedEvents holds every page before --activity-limit.
+	fetchedEvents []GitHubEvent
+	eventsPage    int
+	moreEvents    bool
+	loadingMore   bool
+
+	// Username prompt shown when the user doesn't exist, userInputErr
+	// tells why the last entry was refused
+	userInput    textinput.Model
+	userInputErr string
+
+	// File name prompt of the export key
+	exportInput textinput.Model
+
+	// Masked token prompt shown when a load hits the rate limit without a token
+	tokenInput textinput.Model
+
+	// Watched repositories, fetched the first time their view is opened
+	subscriptions []PublicRepo
+	subsLoaded    bool
+	subsLoading   bool
+
+	// Open PRs and issues from the search API, only fetched with a token
+	openCounts       openCountsLoadedMsg
+	openCountsLoaded bool
+	related          relatedLoadedMsg
+	relatedLoaded    bool
+
+	// Language aggregation across all repos, fetched one repo at a time
+	aggregating bool
+	langRepos   []PublicRepo
+	langDone    int
+	langFailed  int
+	langBytes   map[string]int
 }
 
 func (m Model) Init() tea.Cmd {
-	return tea.Batch(
-		m.spinner.Tick,
-		m.loadData(),
-	)
+	cmds := []tea.Cmd{m.spinner.Tick, m.loadData()}
+	if m.interval > 0 {
+		cmds = append(cmds, watchTickCmd())
+	}
+	if dateLayout == relativeDate {
+		cmds = append(cmds, dateTickCmd())
+	}
+	return tea.Batch(cmds...)
+}
+
+func watchTickCmd() tea.Cmd {
+	return tea.Tick(time.Second, func(time.Time) tea.Msg {
+		return watchTickMsg{}
+	})
+}
+
+// dateRefresh is how often relative dates ("5m ago") are redrawn
+const dateRefresh = 30 * time.Second
+
+// dateTickMsg redraws the relative dates, nothing is fetched
+type dateTickMsg struct{}
+
+func dateTickCmd() tea.Cmd {
+	return tea.Tick(dateRefresh, func(time.Time) tea.Msg {
+		return dateTickMsg{}
+	})
+}
+
+// checkRefreshBudget warns when refreshing every interval would use more
+// requests per hour than the rate limit allows: one per page of 100
+// repositories plus one for the events
+func (m *Model) checkRefreshBudget() {
+	m.watchWarned = true
+	limit := 60
+	if m.client.Token != "" {
+		limit = 5000
+	}
+	perRefresh := 1 + (len(m.publicRepos)+99)/100
+	perHour := perRefresh * int(time.Hour/m.interval)
+	if perHour > limit {
+		m.setNotification(fmt.Sprintf("Refreshing every %v needs about %d requests an hour, over the limit of %d", m.interval, perHour, limit), false)
+	}
 }
 
 func (m Model) loadData() tea.Cmd {
-	return tea.Batch(
-		loadReposCmd(m.username),
-		loadEventsCmd(m.username),
-	)
+	cmds := []tea.Cmd{
+		loadReposCmd(m.loadCtx, m.client, m.username),
+		loadEventsCmd(m.loadCtx, m.client, m.username, m.cfg),
+	}
+	// the search API allows only a few anonymous requests per minute
+	if m.client.Token != "" {
+		cmds = append(cmds, loadOpenCountsCmd(m.loadCtx, m.client, m.username))
+	}
+	cmds = append(cmds, loadAvatarCmd(m.loadCtx, m.client, m.username))
+	return tea.Batch(cmds...)
 }
 
 // Commands
@@ -201,26 +544,157 @@ type eventsLoadedMsg struct {
 	events []GitHubEvent
 	stats  GitHubStats
 	err    error
+
+	fetched []GitHubEvent // every event fetched, before the limit
+	more    bool          // another page can be fetched
+}
+
+// moreEventsLoadedMsg carries the activity after fetching one more page
+type moreEventsLoadedMsg struct {
+	page int
+	eventsLoadedMsg
+}
+
+// subscriptionsLoadedMsg carries the repositories watched by the user
+type subscriptionsLoadedMsg struct {
+	repos []PublicRepo
+	err   error
+}
+
+// openCountsLoadedMsg carries the open PRs and issues authored by the user
+type openCountsLoadedMsg struct {
+	prs    int
+	issues int
+	err    error
+}
+
+// avatarLoadedMsg tells whether login is without a profile picture of
+// their own. A failed check counts as having one.
+type avatarLoadedMsg struct {
+	login    string
+	initials bool
+}
+
+func loadAvatarCmd(ctx context.Context, client *github.Client, username string) tea.Cmd {
+	return func() tea.Msg {
+		profile, err := client.FetchProfile(ctx, username)
+		if err != nil {
+			return avatarLoadedMsg{login: username}
+		}
+		custom, err := client.HasCustomAvatar(ctx, profile.AvatarURL)
+		return avatarLoadedMsg{login: username, initials: err == nil && !custom}
+	}
+}
+
+// languagesProgressMsg carries the languages of one repo during aggregation
+type languagesProgressMsg struct {
+	index     int
+	languages map[string]int
+	err       error
+}
+
+func loadRepoLanguagesCmd(ctx context.Context, client *github.Client, repos []PublicRepo, index int) tea.Cmd {
+	return func() tea.Msg {
+		languages, err := client.FetchRepoLanguages(ctx, repos[index].FullName)
+		return languagesProgressMsg{index: index, languages: languages, err: err}
+	}
+}
+
+func loadOpenCountsCmd(ctx context.Context, client *github.Client, username string) tea.Cmd {
+	return func() tea.Msg {
+		prs, issues, err := client.FetchOpenCounts(ctx, username)
+		return openCountsLoadedMsg{prs: prs, issues: issues, err: err}
+	}
+}
+
+// relatedLoadedMsg carries the repos suggested from the user's topics
+type relatedLoadedMsg struct {
+	topics []string
+	repos  []PublicRepo
+	err    error
+}
+
+// relatedTopics is the number of the user's topics searched for suggestions
+const relatedTopics = 3
+
+func loadRelatedCmd(ctx context.Context, client *github.Client, username string, repos []PublicRepo) tea.Cmd {
+	topics := topTopics(repos, relatedTopics)
+	if len(topics) == 0 {
+		return func() tea.Msg { return relatedLoadedMsg{} }
+	}
+	return func() tea.Msg {
+		related, err := fetchRelatedRepos(ctx, client, topics, username)
+		return relatedLoadedMsg{topics: topics, repos: related, err: err}
+	}
+}
+
+// fundingLoadedMsg carries the FUNDING.yml links of repo
+type fundingLoadedMsg struct {
+	repo  string
+	links []github.FundingLink
+	err   error
+}
+
+func loadFundingCmd(ctx context.Context, client *github.Client, fullName string) tea.Cmd {
+	return func() tea.Msg {
+		links, err := client.FetchFunding(ctx, fullName)
+		return fundingLoadedMsg{repo: fullName, links: links, err: err}
+	}
+}
+
+func loadSubscriptionsCmd(ctx context.Context, client *github.Client, username string) tea.Cmd {
+	return func() tea.Msg {
+		repos, err := client.FetchSubscriptions(ctx, username)
+		return subscriptionsLoadedMsg{repos: repos, err: err}
+	}
 }
 
-func loadReposCmd(username string) tea.Cmd {
+func loadReposCmd(ctx context.Context, client *github.Client, username string) tea.Cmd {
 	return func() tea.Msg {
-		repos, err := fetchPublicRepos(username)
+		repos, err := client.FetchRepos(ctx, username)
 		return reposLoadedMsg{repos: repos, err: err}
 	}
 }
 
-func loadEventsCmd(username string) tea.Cmd {
+func loadEventsCmd(ctx context.Context, client *github.Client, username string, cfg Config) tea.Cmd {
 	return func() tea.Msg {
-		events, err := fetchGitHubActivity(username)
+		events, err := client.FetchActivity(ctx, username, cfg.PublicOnly)
 		if err != nil {
 			return eventsLoadedMsg{err: err}
 		}
-		stats := calculateStats(events)
-		return eventsLoadedMsg{events: events, stats: stats, err: nil}
+		return newEventsLoadedMsg(events, 1, len(events), cfg)
 	}
 }
 
+// loadMoreEventsCmd fetches the given page of activity and adds it to
+// the events fetched so far
+func loadMoreEventsCmd(ctx context.Context, client *github.Client, username string, cfg Config, page int, fetched []GitHubEvent) tea.Cmd {
+	return func() tea.Msg {
+		events, err := client.FetchActivityPage(ctx, username, cfg.PublicOnly, page)
+		if err != nil {
+			return moreEventsLoadedMsg{page: page, eventsLoadedMsg: eventsLoadedMsg{err: err}}
+		}
+		return moreEventsLoadedMsg{page: page, eventsLoadedMsg: newEventsLoadedMsg(mergeEvents(fetched, events), page, len(events), cfg)}
+	}
+}
+
+// newEventsLoadedMsg applies the limit to the events fetched up to page,
+// the last of which had pageLen events
+func newEventsLoadedMsg(fetched []GitHubEvent, page, pageLen int, cfg Config) eventsLoadedMsg {
+	// stats cover every fetched event unless asked to follow the feed. The
+	// types are filtered when rendering, the types key can change them.
+	limited := limitEvents(fetched, cfg.ActivityLimit)
+	stats := calculateStats(fetched)
+	if cfg.LimitStats {
+		stats = calculateStats(feedEvents(fetched, cfg))
+	}
+	// a short page is the last one, and the API serves no more than
+	// MaxEventPages
+	more := pageLen == github.EventsPerPage && page < github.MaxEventPages &&
+		(cfg.ActivityLimit <= 0 || len(fetched) < cfg.ActivityLimit)
+	return eventsLoadedMsg{events: limited, stats: stats, fetched: fetched, more: more}
+}
+
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 	var cmds []tea.Cmd
@@ -230,54 +704,173 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.width = msg.Width
 		m.height = msg.Height
 		m.help.Width = msg.Width
-
-		// Update list dimensions with better centering
-		headerHeight := 4 // Header takes 3-4 lines
-		helpHeight := 3   // Help takes 2-3 lines
-		padding := 4      // Left/right padding
-		availableHeight := msg.Height - headerHeight - helpHeight - 2
-
-		m.list.SetSize(msg.Width-padding, availableHeight)
-
-		// Update table
-		m.updateTableSize()
-
-		// Update viewport with proper sizing
-		m.viewport.Width = msg.Width - padding
-		m.viewport.Height = availableHeight
-
+		m.resize()
 		return m, nil
 
 	case reposLoadedMsg:
+		// superseded by a refresh
+		if errors.Is(msg.err, context.Canceled) {
+			return m, nil
+		}
+		if errors.Is(msg.err, github.ErrUserNotFound) {
+			return m.promptUsername()
+		}
+		// past --max-pages the repos fetched so far are shown
+		capped := errors.Is(msg.err, github.ErrPageLimit)
+		if capped {
+			msg.err = nil
+		}
 		m.reposLoaded = true
+		m.reposErr = msg.err
+		if m.needsToken(msg.err) {
+			m.checkLoadingComplete()
+			return m, m.promptToken()
+		}
 		if msg.err != nil {
-			m.notification = fmt.Sprintf("❌ Error loading repositories: %v", msg.err)
-			m.notifSuccess = false
+			m.setNotification(fmt.Sprintf("❌ Error loading repositories: %v", msg.err), false)
 		} else {
 			m.publicRepos = msg.repos
-			m.updateRepoList()
+			m.dominantLang = dominantLanguage(msg.repos)
+			m.sortRepos()
+			if m.interval > 0 && !m.watchWarned {
+				m.checkRefreshBudget()
+			}
+			if capped {
+				m.setNotification(fmt.Sprintf("Showing the first %d repositories, use --max-pages 0 for all", len(msg.repos)), true)
+			}
+			// the list is shared with the activity view, leave it alone there
+			if m.currentView == repoListView {
+				m.updateRepoList()
+			}
 			m.updateRepoTable()
 		}
 		m.checkLoadingComplete()
+		m.invalidateStats()
+		// suggestions use the search API, too limited without a token
+		if msg.err == nil && m.client.Token != "" {
+			return m, loadRelatedCmd(m.loadCtx, m.client, m.username, m.publicRepos)
+		}
 		return m, nil
 
 	case eventsLoadedMsg:
+		if errors.Is(msg.err, context.Canceled) {
+			return m, nil
+		}
+		if errors.Is(msg.err, github.ErrUserNotFound) {
+			return m.promptUsername()
+		}
 		m.eventsLoaded = true
+		m.eventsErr = msg.err
+		m.loadingMore = false
+		if m.needsToken(msg.err) {
+			m.checkLoadingComplete()
+			return m, m.promptToken()
+		}
 		if msg.err != nil {
-			m.notification = fmt.Sprintf("❌ Error loading activity: %v", msg.err)
-			m.notifSuccess = false
+			m.setNotification(fmt.Sprintf("❌ Error loading activity: %v", msg.err), false)
 		} else {
-			m.events = msg.events
-			m.stats = msg.stats
-			m.updateActivityList()
+			m.setEvents(msg)
+			m.eventsPage = 1
+		}
+		m.checkLoadingComplete()
+		m.invalidateStats()
+		return m, nil
+
+	case moreEventsLoadedMsg:
+		// superseded by a refresh
+		if errors.Is(msg.err, context.Canceled) || msg.page != m.eventsPage+1 {
+			return m, nil
+		}
+		m.loadingMore = false
+		if msg.err != nil {
+			// the events loaded so far stay, scrolling won't retry
+			m.moreEvents = false
+			if m.currentView == activityView {
+				m.updateActivityList()
+			}
+			return m, notifyCmd(fmt.Sprintf("❌ Error loading more activity: %v", msg.err), false)
+		}
+		m.setEvents(msg.eventsLoadedMsg)
+		m.eventsPage = msg.page
+		m.invalidateStats()
+		return m, nil
+
+	case subscriptionsLoadedMsg:
+		if errors.Is(msg.err, context.Canceled) {
+			return m, nil
+		}
+		m.subsLoading = false
+		m.subsLoaded = true
+		if msg.err != nil && !errors.Is(msg.err, github.ErrPageLimit) {
+			m.setNotification(fmt.Sprintf("❌ Error loading watched repositories: %v", msg.err), false)
+		}
+		m.subscriptions = msg.repos
+		if m.currentView == subscriptionsView {
+			m.updateSubscriptionsList()
+		}
+		return m, nil
+
+	case relatedLoadedMsg:
+		if errors.Is(msg.err, context.Canceled) {
+			return m, nil
+		}
+		m.related = msg
+		m.relatedLoaded = true
+		m.invalidateStats()
+		return m, nil
+
+	case avatarLoadedMsg:
+		// a check for the previous username can arrive after a switch
+		if strings.EqualFold(msg.login, m.username) {
+			m.initials = msg.initials
+		}
+		return m, nil
+
+	case openCountsLoadedMsg:
+		if errors.Is(msg.err, context.Canceled) {
+			return m, nil
 		}
+		m.openCounts = msg
+		m.openCountsLoaded = true
 		m.checkLoadingComplete()
+		m.invalidateStats()
 		return m, nil
 
+	case languagesProgressMsg:
+		// ignore results arriving after a cancel or from a previous run
+		if !m.aggregating || msg.index != m.langDone {
+			return m, nil
+		}
+		// a watch refresh cancelled the request, it is made again
+		if errors.Is(msg.err, context.Canceled) {
+			return m, loadRepoLanguagesCmd(m.loadCtx, m.client, m.langRepos, m.langDone)
+		}
+		m.langDone++
+		if msg.err != nil {
+			m.langFailed++
+		} else {
+			for lang, bytes := range msg.languages {
+				m.langBytes[lang] += bytes
+			}
+		}
+		m.invalidateStats()
+
+		if m.langDone < len(m.langRepos) {
+			return m, loadRepoLanguagesCmd(m.loadCtx, m.client, m.langRepos, m.langDone)
+		}
+		m.aggregating = false
+		return m, notifyCmd(fmt.Sprintf("Languages analyzed across %d repos (%d failed)", m.langDone, m.langFailed), true)
+
+	case actionStartedMsg:
+		m.working = msg.label
+		return m, m.spinner.Tick
+
 	case NotificationMsg:
+		// the result of the running action
+		m.working = ""
 		m.notification = msg.message
 		m.notifSuccess = msg.isSuccess
-		return m, tea.Tick(3*time.Second, func(t time.Time) tea.Msg {
+		return m, tea.Tick(m.notifDuration, func(t time.Time) tea.Msg {
 			return ClearNotificationMsg{}
 		})
 
@@ -285,63 +878,256 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.notification = ""
 		return m, nil
 
+	case fundingLoadedMsg:
+		switch {
+		case errors.Is(msg.err, context.Canceled):
+		case msg.err != nil:
+			m.setNotification(fmt.Sprintf("❌ Error loading funding of %s: %v", msg.repo, msg.err), false)
+		case len(msg.links) == 0:
+			m.setNotification(fmt.Sprintf("%s has no funding links", msg.repo), true)
+		default:
+			urls := make([]string, len(msg.links))
+			for i, link := range msg.links {
+				urls[i] = link.URL
+			}
+			m.setNotification(fmt.Sprintf("Support %s: %s", msg.repo, strings.Join(urls, " • ")), true)
+		}
+		return m, nil
+
+	case watchTickMsg:
+		if m.askUser || m.askToken || time.Now().Before(m.nextRefresh) {
+			return m, watchTickCmd()
+		}
+		m.nextRefresh = time.Now().Add(m.interval)
+		return m, tea.Batch(watchTickCmd(), m.refresh())
+
+	case dateTickMsg:
+		// the lists format their dates on every frame, the table rows and
+		// the stats are rendered ahead
+		m.refreshTableDates()
+		m.invalidateStats()
+		return m, dateTickCmd()
+
+	case viewFlashEndMsg:
+		if msg.seq == m.viewFlashSeq {
+			m.viewFlash = false
+		}
+		return m, nil
+
 	case spinner.TickMsg:
 		m.spinner, cmd = m.spinner.Update(msg)
 		return m, cmd
 
 	case tea.KeyMsg:
+		if m.askUser {
+			return m.handleUsernameInput(msg)
+		}
+		if m.askToken {
+			return m.handleTokenInput(msg)
+		}
 		if m.searchMode {
 			return m.handleSearchInput(msg)
 		}
+		if m.exportMode {
+			return m.handleExportInput(msg)
+		}
+
+		// the native filter takes every key while typing, esc clears it
+		if m.list.FilterState() == list.Filtering ||
+			m.list.FilterState() == list.FilterApplied && msg.Type == tea.KeyEsc {
+			m.list, cmd = m.list.Update(msg)
+			return m, cmd
+		}
+
+		// esc stops a running aggregation instead of quitting, keeping partial results
+		if m.aggregating && msg.Type == tea.KeyEsc {
+			m.aggregating = false
+			m.updateStatsView()
+			return m, notifyCmd(fmt.Sprintf("Language analysis cancelled: %d/%d repos analyzed", m.langDone, len(m.langRepos)), true)
+		}
+
+		// any key closes the comparison or the details, ctrl+c still quits
+		if (m.comparing || m.detail != nil) && msg.Type != tea.KeyCtrlC {
+			m.comparing = false
+			m.detail = nil
+			return m, nil
+		}
 
 		switch {
 		case key.Matches(msg, keys.Quit):
+			m.cancelLoad()
 			return m, tea.Quit
 
 		case key.Matches(msg, keys.Help):
 			m.help.ShowAll = !m.help.ShowAll
 			return m, nil
 
+		case key.Matches(msg, keys.Header):
+			m.compactHeader = !m.compactHeader
+			m.resize()
+			return m, nil
+
+		case key.Matches(msg, keys.Sort):
+			if m.currentView == repoListView || m.currentView == repoTableView {
+				m.sortByPopularity = !m.sortByPopularity
+				m.sortRepos()
+				m.invalidateStats()
+				if m.currentView == repoListView {
+					m.updateRepoList()
+				}
+				m.updateRepoTable()
+				if m.sortByPopularity {
+					return m, notifyCmd("Sorted by popularity score", true)
+				}
+				return m, notifyCmd("Sorted by stars", true)
+			}
+
 		case key.Matches(msg, keys.Tab):
 			m.nextView()
-			return m, nil
+			flash := m.flashView()
+			// watched repos are only fetched once their view is opened
+			if m.currentView == subscriptionsView && !m.subsLoaded && !m.subsLoading {
+				m.subsLoading = true
+				return m, tea.Batch(flash, loadSubscriptionsCmd(m.loadCtx, m.client, m.username))
+			}
+			return m, flash
+
+		case key.Matches(msg, keys.Export):
+			if m.currentView != statsView {
+				m.exportMode = true
+				m.exportInput.SetValue("")
+				m.exportInput.Focus()
+				return m, textinput.Blink
+			}
 
-		case key.Matches(msg, keys.Search):
+		// with the native filter, / is left to the list
+		case key.Matches(msg, keys.Search) && !m.cfg.NativeFilter:
 			if m.currentView == repoListView {
 				m.searchMode = true
 				m.search.Focus()
 				return m, textinput.Blink
 			}
 
+		case key.Matches(msg, keys.Langs):
+			if m.currentView == statsView && !m.aggregating && len(m.publicRepos) > 0 {
+				m.aggregating = true
+				m.langRepos = append([]PublicRepo(nil), m.publicRepos...)
+				m.langDone = 0
+				m.langFailed = 0
+				m.langBytes = make(map[string]int)
+				m.updateStatsView()
+				return m, loadRepoLanguagesCmd(m.loadCtx, m.client, m.langRepos, 0)
+			}
+
+		case key.Matches(msg, keys.Enter):
+			if m.currentView == repoListView || m.currentView == repoTableView {
+				if repo, ok := m.selectedRepo(); ok {
+					m.detail = &repo
+					return m, nil
+				}
+			}
+
+		case key.Matches(msg, keys.Jump):
+			if m.currentView == activityView {
+				if item, ok := m.list.SelectedItem().(activityItem); ok {
+					return m, m.jumpToRepo(item.event.Repo.Name)
+				}
+			}
+
+		case key.Matches(msg, keys.Retry):
+			if cmd := m.retryFailed(); cmd != nil {
+				m.setNotification("Retrying...", true)
+				return m, cmd
+			}
+
 		case key.Matches(msg, keys.Refresh):
-			m.loading = true
-			m.reposLoaded = false
-			m.eventsLoaded = false
-			m.notification = "Refreshing data..."
-			m.notifSuccess = true
-			return m, m.loadData()
+			m.nextRefresh = time.Now().Add(m.interval)
+			m.setNotification("Refreshing data...", true)
+			return m, m.reload()
 
 		case key.Matches(msg, keys.Clone):
-			if m.currentView == repoListView && len(m.publicRepos) > 0 {
+			if m.showsRepoItems() {
 				selected := m.list.SelectedItem()
 				if repoItem, ok := selected.(repoItem); ok {
-					return m, m.cloneRepo(repoItem.repo)
+					return m, startAction("Copying the clone command of "+repoItem.repo.Name, m.cloneRepo(repoItem.repo))
 				}
 			}
 
 		case key.Matches(msg, keys.Copy):
-			if m.currentView == repoListView && len(m.publicRepos) > 0 {
+			if m.showsRepoItems() {
 				selected := m.list.SelectedItem()
 				if repoItem, ok := selected.(repoItem); ok {
-					return m, m.copyURL(repoItem.repo)
+					return m, startAction("Copying the URL of "+repoItem.repo.Name, m.copyURL(repoItem.repo))
+				}
+			}
+			if m.currentView == activityView {
+				if item, ok := m.list.SelectedItem().(activityItem); ok {
+					return m, startAction("Copying the URL of "+item.event.Repo.Name,
+						copyText(repoWebURL(item.event.Repo), "URL copied: "+item.event.Repo.Name))
+				}
+			}
+
+		case key.Matches(msg, keys.Stats):
+			if m.currentView == statsView && m.reposLoaded && m.eventsLoaded {
+				return m, m.copyStatsJSON()
+			}
+
+		case key.Matches(msg, keys.Types):
+			if m.currentView == activityView && m.eventsLoaded {
+				m.cycleEventTypes()
+				return m, m.maybeLoadMoreEvents()
+			}
+
+		case key.Matches(msg, keys.Primary):
+			if m.currentView == repoListView && m.reposLoaded {
+				return m, m.toggleDominantLang()
+			}
+
+		case key.Matches(msg, keys.Mark):
+			if m.currentView == repoListView {
+				if item, ok := m.list.SelectedItem().(repoItem); ok {
+					return m, m.toggleMark(item.repo)
 				}
 			}
 
+		case key.Matches(msg, keys.Compare):
+			if m.currentView == repoListView {
+				return m, m.compareMarked()
+			}
+
+		case key.Matches(msg, keys.Website):
+			if repo, ok := m.selectedRepo(); ok {
+				return m, m.openHomepage(repo)
+			}
+
+		case key.Matches(msg, keys.Badge):
+			if repo, ok := m.selectedRepo(); ok {
+				return m, m.copyStarsBadge(repo)
+			}
+
+		case key.Matches(msg, keys.Funding):
+			if repo, ok := m.selectedRepo(); ok {
+				return m, loadFundingCmd(m.loadCtx, m.client, repo.FullName)
+			}
+
+		case key.Matches(msg, keys.Link):
+			if repo, ok := m.selectedRepo(); ok {
+				return m, m.copyMarkdownLink(repo)
+			}
+
 		case key.Matches(msg, keys.Open):
-			if m.currentView == repoListView && len(m.publicRepos) > 0 {
+			if m.showsRepoItems() {
 				selected := m.list.SelectedItem()
 				if repoItem, ok := selected.(repoItem); ok {
-					return m, m.openInBrowser(repoItem.repo)
+					return m, startAction("Opening "+repoItem.repo.Name, m.openInBrowser(repoItem.repo))
+				}
+			}
+			if m.currentView == activityView {
+				if item, ok := m.list.SelectedItem().(activityItem); ok {
+					if release := item.event.Payload.Release; release != nil && release.URL != "" {
+						return m, startAction("Opening "+release.TagName, openURL(release.URL, release.TagName))
+					}
+					return m, startAction("Opening "+item.event.Repo.Name, openURL(repoWebURL(item.event.Repo), item.event.Repo.Name))
 				}
 			}
 		}
@@ -354,6 +1140,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.table, cmd = m.table.Update(msg)
 		case activityView:
 			m.list, cmd = m.list.Update(msg)
+			cmd = tea.Batch(cmd, m.maybeLoadMoreEvents())
+		case subscriptionsView:
+			m.list, cmd = m.list.Update(msg)
 		case statsView:
 			m.viewport, cmd = m.viewport.Update(msg)
 		}
@@ -370,6 +1159,233 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
+// reload aborts requests still running from the previous load and
+// fetches everything again
+func (m *Model) reload() tea.Cmd {
+	m.cancelLoad()
+	m.loadCtx, m.cancelLoad = context.WithCancel(context.Background())
+	m.aggregating = false
+	m.statsStale = true
+	m.loading = true
+	m.reposLoaded = false
+	m.openCountsLoaded = false
+	m.relatedLoaded = false
+	m.eventsLoaded = false
+	m.eventsPage = 0
+	m.moreEvents = false
+	m.loadingMore = false
+	m.reposErr = nil
+	m.eventsErr = nil
+	m.subsLoaded = false
+	m.subsLoading = false
+	if m.currentView == subscriptionsView {
+		m.subsLoading = true
+		return tea.Batch(m.loadData(), loadSubscriptionsCmd(m.loadCtx, m.client, m.username))
+	}
+	return m.loadData()
+}
+
+// refresh refetches the data in the background for the watch mode. Like
+// reload the requests of the previous load are cancelled, but nothing is
+// reset: the current data stays on screen, the language aggregation goes
+// on, and the load handlers swap the new data in.
+func (m *Model) refresh() tea.Cmd {
+	m.cancelLoad()
+	m.loadCtx, m.cancelLoad = context.WithCancel(context.Background())
+	cmds := []tea.Cmd{m.loadData()}
+	if m.subsLoaded {
+		cmds = append(cmds, loadSubscriptionsCmd(m.loadCtx, m.client, m.username))
+	}
+	return tea.Batch(cmds...)
+}
+
+// retryFailed reloads only the sections whose last load failed
+func (m *Model) retryFailed() tea.Cmd {
+	var cmds []tea.Cmd
+	if m.reposErr != nil {
+		m.reposErr = nil
+		cmds = append(cmds, loadReposCmd(m.loadCtx, m.client, m.username))
+	}
+	if m.eventsErr != nil {
+		m.eventsErr = nil
+		cmds = append(cmds, loadEventsCmd(m.loadCtx, m.client, m.username, m.cfg))
+	}
+	if len(cmds) == 0 {
+		return nil
+	}
+	return tea.Batch(cmds...)
+}
+
+// promptUsername asks for another username after the current one
+// turned out not to exist
+func (m Model) promptUsername() (tea.Model, tea.Cmd) {
+	if m.askUser {
+		return m, nil
+	}
+	m.cancelLoad()
+	m.askUser = true
+	m.userInput.SetValue(m.username)
+	m.userInput.CursorEnd()
+	return m, m.userInput.Focus()
+}
+
+func (m *Model) handleUsernameInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg.Type {
+	case tea.KeyEsc, tea.KeyCtrlC:
+		return m, tea.Quit
+
+	case tea.KeyEnter:
+		if strings.TrimSpace(m.userInput.Value()) == "" {
+			return m, nil
+		}
+		username, err := normalizeUsername(m.userInput.Value())
+		if err != nil {
+			m.userInputErr = err.Error()
+			return m, nil
+		}
+		m.userInputErr = ""
+		m.askUser = false
+		m.userInput.Blur()
+		m.username = username
+		m.lastSeen = lastSeen(username)
+		m.initials = false
+		m.notification = ""
+		return m, tea.Batch(m.spinner.Tick, m.reload())
+	}
+
+	m.userInput, cmd = m.userInput.Update(msg)
+	return m, cmd
+}
+
+// needsToken tells if err is the rate limit of a session without a token,
+// which a token would lift
+func (m Model) needsToken(err error) bool {
+	return errors.Is(err, github.ErrRateLimited) && m.client.Token == ""
+}
+
+// promptToken asks for a token after a load hit the rate limit. Both
+// sections can fail, the prompt only opens once.
+func (m *Model) promptToken() tea.Cmd {
+	if m.askToken {
+		return nil
+	}
+	m.askToken = true
+	m.tokenInput.SetValue("")
+	return m.tokenInput.Focus()
+}
+
+func (m *Model) handleTokenInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		return m, tea.Quit
+
+	case tea.KeyEsc:
+		// the failed sections stay failed until the retry key fetches them again
+		m.askToken = false
+		m.tokenInput.Blur()
+		m.setNotification(fmt.Sprintf("❌ %v, set GITHUB_TOKEN for a higher limit or press %s to retry",
+			github.ErrRateLimited, keys.Retry.Help().Key), false)
+		return m, nil
+
+	case tea.KeyEnter:
+		token := sanitizeToken(m.tokenInput.Value())
+		if token == "" {
+			return m, nil
+		}
+		m.askToken = false
+		m.tokenInput.Blur()
+		m.tokenInput.SetValue("")
+		// commands still running hold the old client, it isn't changed
+		m.client = m.client.WithToken(token)
+		m.loading = true
+		m.setNotification("Token set, retrying...", true)
+		return m, tea.Batch(m.spinner.Tick, m.retryFailed())
+	}
+
+	m.tokenInput, cmd = m.tokenInput.Update(msg)
+	return m, cmd
+}
+
+func (m *Model) handleExportInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg.Type {
+	case tea.KeyEsc, tea.KeyCtrlC:
+		m.exportMode = false
+		m.exportInput.Blur()
+		return m, nil
+
+	case tea.KeyEnter:
+		path := strings.TrimSpace(m.exportInput.Value())
+		if path == "" {
+			return m, nil
+		}
+		m.exportMode = false
+		m.exportInput.Blur()
+		return m, m.exportView(path)
+	}
+
+	m.exportInput, cmd = m.exportInput.Update(msg)
+	return m, cmd
+}
+
+// exportView writes what the current view shows to path, as CSV when the
+// name ends in .csv and JSON otherwise, like --repos and --activity do.
+// The list views export their filtered items.
+func (m Model) exportView(path string) tea.Cmd {
+	asCSV := strings.EqualFold(filepath.Ext(path), ".csv")
+
+	var write func(io.Writer) error
+	switch m.currentView {
+	case repoListView, subscriptionsView:
+		var repos []PublicRepo
+		for _, item := range m.list.Items() {
+			if item, ok := item.(repoItem); ok {
+				repos = append(repos, item.repo)
+			}
+		}
+		write = func(w io.Writer) error { return writeRepos(w, repos, asCSV) }
+	case repoTableView:
+		repos := m.publicRepos
+		write = func(w io.Writer) error { return writeRepos(w, repos, asCSV) }
+	case activityView:
+		if asCSV {
+			return notifyCmd("❌ Activity can only be exported as JSON", false)
+		}
+		events := filterEventTypes(m.events, m.activityTypes())
+		write = func(w io.Writer) error { return writeActivityJSON(w, events, false) }
+	default:
+		return notifyCmd("❌ Nothing to export in this view", false)
+	}
+
+	return func() tea.Msg {
+		f, err := os.Create(path)
+		if err != nil {
+			return notify(fmt.Sprintf("❌ Export Error: %v", err), false)
+		}
+		err = write(f)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return notify(fmt.Sprintf("❌ Export Error: %v", err), false)
+		}
+		return notify(fmt.Sprintf("Exported to %s", path), true)
+	}
+}
+
+// writeRepos writes repos with every export field, as CSV or JSON
+func writeRepos(w io.Writer, repos []PublicRepo, asCSV bool) error {
+	if asCSV {
+		return writeReposCSV(w, repos, repoFieldNames)
+	}
+	return writeReposJSON(w, repos, repoFieldNames)
+}
+
 func (m *Model) handleSearchInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 
@@ -384,15 +1400,150 @@ func (m *Model) handleSearchInput(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case tea.KeyEnter:
 		m.searchMode = false
 		m.search.Blur()
-		m.filterRepoList(m.search.Value())
-		return m, nil
+		return m, m.filterRepoList(m.search.Value())
 	}
 
 	m.search, cmd = m.search.Update(msg)
 	return m, cmd
 }
 
+// selectedRepo returns the repository under the cursor in the list or table view
+func (m Model) selectedRepo() (PublicRepo, bool) {
+	switch m.currentView {
+	case repoListView, subscriptionsView:
+		if item, ok := m.list.SelectedItem().(repoItem); ok {
+			return item.repo, true
+		}
+	case repoTableView:
+		if i := m.table.Cursor(); i >= 0 && i < len(m.publicRepos) {
+			return m.publicRepos[i], true
+		}
+	}
+	return PublicRepo{}, false
+}
+
+// isMarked reports whether repo is marked to be compared
+func (m Model) isMarked(repo PublicRepo) bool {
+	return slices.Contains(m.marked, repo.FullName)
+}
+
+// toggleMark marks repo to be compared, or unmarks it. Marking a third
+// repo drops the oldest mark.
+func (m *Model) toggleMark(repo PublicRepo) tea.Cmd {
+	if i := slices.Index(m.marked, repo.FullName); i >= 0 {
+		m.marked = slices.Delete(m.marked, i, i+1)
+	} else {
+		if len(m.marked) == 2 {
+			m.marked = m.marked[1:]
+		}
+		m.marked = append(m.marked, repo.FullName)
+	}
+
+	// refresh the marks of the shown items, the dropped one included
+	for i, item := range m.list.Items() {
+		if repoItem, ok := item.(repoItem); ok {
+			repoItem.marked = m.isMarked(repoItem.repo)
+			m.list.SetItem(i, repoItem)
+		}
+	}
+	return notifyCmd(fmt.Sprintf("%d/2 repositories marked, v to compare", len(m.marked)), true)
+}
+
+// compareMarked shows the two marked repos side by side. Marks of repos
+// gone after a refresh are forgotten.
+func (m *Model) compareMarked() tea.Cmd {
+	m.marked = slices.DeleteFunc(m.marked, func(fullName string) bool {
+		return !slices.ContainsFunc(m.publicRepos, func(r PublicRepo) bool { return r.FullName == fullName })
+	})
+	if len(m.marked) < 2 {
+		return notifyCmd(fmt.Sprintf("Mark two repositories with space to compare them (%d/2 marked)", len(m.marked)), false)
+	}
+	m.comparing = true
+	return nil
+}
+
+// sortRepos orders the repositories by stars, or by popularity score
+// when toggled. The list and table follow this order.
+func (m *Model) sortRepos() {
+	if m.sortByPopularity {
+		now := time.Now()
+		sort.SliceStable(m.publicRepos, func(i, j int) bool {
+			return popularityScoreAt(m.publicRepos[i], now) > popularityScoreAt(m.publicRepos[j], now)
+		})
+		return
+	}
+	sort.SliceStable(m.publicRepos, func(i, j int) bool {
+		return m.publicRepos[i].Stars > m.publicRepos[j].Stars
+	})
+}
+
+// showsRepoItems reports whether the list holds repositories
+func (m Model) showsRepoItems() bool {
+	return m.currentView == repoListView || m.currentView == subscriptionsView
+}
+
+// typing reports whether a text input has the keyboard
+func (m Model) typing() bool {
+	return m.searchMode || m.askUser || m.askToken || m.exportMode || m.list.FilterState() == list.Filtering
+}
+
+// jumpToRepo switches to the repo list with fullName selected, or explains
+// why it can't when the repo isn't one of the user's own
+func (m *Model) jumpToRepo(fullName string) tea.Cmd {
+	owned := slices.ContainsFunc(m.publicRepos, func(repo PublicRepo) bool {
+		return strings.EqualFold(repo.FullName, fullName)
+	})
+	if !owned {
+		return notifyCmd(fmt.Sprintf("%s is not one of %s's public repositories", fullName, m.username), false)
+	}
+
+	// the list can hold fewer repos than publicRepos, select by name
+	itemIndex := func() int {
+		return slices.IndexFunc(m.list.Items(), func(item list.Item) bool {
+			repoItem, ok := item.(repoItem)
+			return ok && strings.EqualFold(repoItem.repo.FullName, fullName)
+		})
+	}
+
+	m.currentView = repoListView
+	m.list.ResetFilter()
+	m.updateRepoList()
+	i := itemIndex()
+	if i < 0 {
+		// hidden by the language filter, show every repo
+		m.langOnly = false
+		m.updateRepoList()
+		i = itemIndex()
+	}
+	m.list.Select(i)
+	return nil
+}
+
+// viewFlashDuration is how long the view name stays highlighted
+const viewFlashDuration = 600 * time.Millisecond
+
+// flashView highlights the view name in the header for a moment, so a
+// switch is noticed. Off with the no_motion setting.
+func (m *Model) flashView() tea.Cmd {
+	if m.cfg.NoMotion {
+		return nil
+	}
+	m.viewFlash = true
+	m.viewFlashSeq++
+	seq := m.viewFlashSeq
+	return tea.Tick(viewFlashDuration, func(time.Time) tea.Msg {
+		return viewFlashEndMsg{seq: seq}
+	})
+}
+
 func (m *Model) nextView() {
+	// remember where the list was, it is rebuilt for the next list view
+	if m.currentView == repoListView || m.currentView == activityView || m.currentView == subscriptionsView {
+		m.cursors[m.currentView] = m.list.Index()
+	}
+	// a native filter doesn't carry over to the next list
+	m.list.ResetFilter()
+
 	switch m.currentView {
 	case repoListView:
 		m.currentView = repoTableView
@@ -401,6 +1552,8 @@ func (m *Model) nextView() {
 	case statsView:
 		m.currentView = activityView
 	case activityView:
+		m.currentView = subscriptionsView
+	case subscriptionsView:
 		m.currentView = repoListView
 	}
 
@@ -408,64 +1561,260 @@ func (m *Model) nextView() {
 	switch m.currentView {
 	case repoListView:
 		m.updateRepoList()
+		m.restoreCursor()
 	case activityView:
 		m.updateActivityList()
+		m.restoreCursor()
+	case subscriptionsView:
+		m.updateSubscriptionsList()
+		m.restoreCursor()
 	case statsView:
-		m.updateStatsView()
+		if m.statsStale {
+			m.updateStatsView()
+		}
+	}
+}
+
+// restoreCursor selects the item remembered for the current view, clamped
+// in case a refresh left fewer items
+func (m *Model) restoreCursor() {
+	if n := len(m.list.Items()); n > 0 {
+		m.list.Select(min(m.cursors[m.currentView], n-1))
+	}
+}
+
+// loadingSource is one of the requests made by loadData
+type loadingSource struct {
+	name   string
+	loaded bool
+}
+
+// loadingSources lists what loadData fetches, in the order shown while
+// loading. A new data source only needs an entry here.
+func (m Model) loadingSources() []loadingSource {
+	sources := []loadingSource{
+		{"repositories", m.reposLoaded},
+		{"activity", m.eventsLoaded},
+	}
+	if m.client.Token != "" {
+		sources = append(sources, loadingSource{"open PRs and issues", m.openCountsLoaded})
+	}
+	return sources
+}
+
+func (m *Model) checkLoadingComplete() {
+	for _, source := range m.loadingSources() {
+		if !source.loaded {
+			return
+		}
+	}
+	m.loading = false
+}
+
+func (m *Model) updateRepoList() {
+	if m.langOnly && m.dominantLang != "" {
+		m.showLanguage(m.dominantLang)
+		return
+	}
+	items := make([]list.Item, len(m.publicRepos))
+	for i, repo := range m.publicRepos {
+		items[i] = repoItem{repo: repo, marked: m.isMarked(repo)}
+	}
+	m.list.SetItems(items)
+	m.list.Title = fmt.Sprintf("℗ Public Repositories (%d)", len(m.publicRepos))
+}
+
+// showLanguage lists the repos written in lang
+func (m *Model) showLanguage(lang string) {
+	q := repoQuery{lang: strings.ToLower(lang), minStars: -1, maxStars: -1}
+	var items []list.Item
+	for _, repo := range m.publicRepos {
+		if q.matches(repo) {
+			items = append(items, repoItem{repo: repo, marked: m.isMarked(repo)})
+		}
+	}
+	m.list.SetItems(items)
+	m.list.Title = fmt.Sprintf("℗ %s Repositories (%d of %d)", lang, len(items), len(m.publicRepos))
+}
+
+// toggleDominantLang switches the repo list between every repo and the
+// ones in the most common language
+func (m *Model) toggleDominantLang() tea.Cmd {
+	if m.dominantLang == "" {
+		return notifyCmd("No repository has a detected language", false)
+	}
+	m.langOnly = !m.langOnly
+	m.updateRepoList()
+	return nil
+}
+
+func (m *Model) updateActivityList() {
+	var items []list.Item
+	newEvents := 0
+	for _, c := range m.activityEvents() {
+		// nothing is new on the first visit
+		isNew := !m.lastSeen.IsZero() && c.event.CreatedAt.After(m.lastSeen)
+		if isNew {
+			newEvents += c.count
+		}
+		items = append(items, activityItem{event: c.event, selfLogin: m.username, count: c.count, isNew: isNew})
+	}
+	m.list.SetItems(items)
+	count := fmt.Sprintf("%d events", len(m.events))
+	if types := m.activityTypes(); len(types) > 0 {
+		count = fmt.Sprintf("%d of %d events, %s", len(filterEventTypes(m.events, types)), len(m.events), strings.Join(types, ","))
+	}
+	m.list.Title = fmt.Sprintf("𐧾 Recent Activity (%s)", count)
+	if newEvents > 0 {
+		m.list.Title = fmt.Sprintf("𐧾 Recent Activity (%s, %d new since last visit)", count, newEvents)
+	}
+	switch {
+	case m.loadingMore:
+		m.list.Title += " · loading more…"
+	case m.moreEvents:
+		m.list.Title += " · scroll for more"
+	}
+}
+
+// setEvents shows the activity of a load or of one more page
+func (m *Model) setEvents(msg eventsLoadedMsg) {
+	m.events = msg.events
+	m.fetchedEvents = msg.fetched
+	m.moreEvents = msg.more
+	m.stats = msg.stats
+	if m.currentView == activityView {
+		m.updateActivityList()
+	}
+}
+
+// loadMoreThreshold is how close to the end of the activity list the
+// cursor gets before the next page is fetched
+const loadMoreThreshold = 5
+
+// maybeLoadMoreEvents fetches the next page of activity when the cursor
+// is near the end of the list and no page is on its way
+func (m *Model) maybeLoadMoreEvents() tea.Cmd {
+	if !m.moreEvents || m.loadingMore || m.list.Index() < len(m.list.Items())-loadMoreThreshold {
+		return nil
 	}
+	m.loadingMore = true
+	m.updateActivityList()
+	return loadMoreEventsCmd(m.loadCtx, m.client, m.username, m.cfg, m.eventsPage+1, m.fetchedEvents)
 }
 
-func (m *Model) checkLoadingComplete() {
-	if m.reposLoaded && m.eventsLoaded {
-		m.loading = false
-		m.ready = true
+// activityEvents returns the items of the activity list, the events of
+// activityTypes with pushes merged when --collapse is set
+func (m Model) activityEvents() []collapsedEvent {
+	shown := filterEventTypes(m.events, m.activityTypes())
+	if m.cfg.Collapse {
+		return collapseEvents(shown, collapseWindow)
+	}
+	events := make([]collapsedEvent, len(shown))
+	for i, event := range shown {
+		events[i] = collapsedEvent{event: event, count: 1}
 	}
+	return events
 }
 
-func (m *Model) updateRepoList() {
-	items := make([]list.Item, len(m.publicRepos))
-	for i, repo := range m.publicRepos {
-		items[i] = repoItem{repo: repo}
+// activityTypes returns the eventTypeNames the activity list shows, nil
+// for all of them
+func (m Model) activityTypes() []string {
+	if m.typeCycle > 0 {
+		return []string{eventTypeOrder[m.typeCycle-1]}
 	}
-	m.list.SetItems(items)
-	m.list.Title = fmt.Sprintf("℗ Public Repositories (%d)", len(m.publicRepos))
+	// an invalid list was reported at startup and keeps every event
+	names, _ := parseEventTypes(m.cfg.EventTypes)
+	return names
 }
 
-func (m *Model) updateActivityList() {
-	items := make([]list.Item, len(m.events))
-	for i, event := range m.events {
-		items[i] = activityItem{event: event}
+// cycleEventTypes shows the events of the next type of eventTypeOrder,
+// back to the --event-types selection after the last one
+func (m *Model) cycleEventTypes() {
+	m.typeCycle = (m.typeCycle + 1) % (len(eventTypeOrder) + 1)
+	m.list.ResetSelected()
+	m.updateActivityList()
+}
+
+// saveLastSeen remembers the newest loaded event, so the next session
+// can tell the new ones
+func (m Model) saveLastSeen() error {
+	var newest time.Time
+	for _, event := range m.events {
+		if event.CreatedAt.After(newest) {
+			newest = event.CreatedAt
+		}
+	}
+	if newest.IsZero() {
+		return nil
+	}
+	return saveLastSeen(m.username, newest)
+}
+
+func (m *Model) updateSubscriptionsList() {
+	items := make([]list.Item, len(m.subscriptions))
+	for i, repo := range m.subscriptions {
+		items[i] = repoItem{repo: repo}
 	}
 	m.list.SetItems(items)
-	m.list.Title = fmt.Sprintf("𐧾 Recent Activity (%d events)", len(m.events))
+	if len(m.subscriptions) == 0 && m.subsLoaded {
+		m.list.Title = fmt.Sprintf("◉ %s isn't watching any public repositories", m.username)
+		return
+	}
+	m.list.Title = fmt.Sprintf("◉ Watching (%d repositories)", len(m.subscriptions))
 }
 
-func (m *Model) filterRepoList(query string) {
-	if query == "" {
+// filterRepoList shows the repos matching a search query, see parseRepoQuery
+func (m *Model) filterRepoList(query string) tea.Cmd {
+	// a search replaces the language filter
+	m.langOnly = false
+	if strings.TrimSpace(query) == "" {
 		m.updateRepoList()
-		return
+		return nil
+	}
+
+	q, err := parseRepoQuery(query)
+	if err != nil {
+		return notifyCmd(fmt.Sprintf("❌ Invalid search: %v", err), false)
 	}
 
 	var filtered []list.Item
 	for _, repo := range m.publicRepos {
-		if strings.Contains(strings.ToLower(repo.Name), strings.ToLower(query)) ||
-			strings.Contains(strings.ToLower(repo.Description), strings.ToLower(query)) {
-			filtered = append(filtered, repoItem{repo: repo})
+		if q.matches(repo) {
+			filtered = append(filtered, repoItem{repo: repo, marked: m.isMarked(repo)})
 		}
 	}
 	m.list.SetItems(filtered)
 	m.list.Title = fmt.Sprintf("𐧻 Repositories matching '%s' (%d)", query, len(filtered))
+	return nil
+}
+
+// refreshTableDates formats the Updated column again, keeping the
+// cursor unlike updateRepoTable
+func (m *Model) refreshTableDates() {
+	rows := m.table.Rows()
+	if len(rows) != len(m.publicRepos) {
+		return
+	}
+	for i, repo := range m.publicRepos {
+		// Updated is the last column
+		rows[i][len(rows[i])-1] = formatDate(repo.UpdatedAt)
+	}
+	m.table.SetRows(rows)
 }
 
 func (m *Model) updateRepoTable() {
 	columns := []table.Column{
 		{Title: "Name", Width: 25},
+		{Title: "Score", Width: 8},
 		{Title: "Stars", Width: 8},
 		{Title: "Forks", Width: 8},
 		{Title: "Language", Width: 12},
-		{Title: "Updated", Width: 12},
+		{Title: "Updated", Width: max(12, len(formatDate(time.Now())))},
 	}
 
+	// a refresh rebuilds the table under the cursor, keep it in place
+	cursor := m.table.Cursor()
+
 	var rows []table.Row
 	for _, repo := range m.publicRepos {
 		lang := repo.Language
@@ -473,11 +1822,12 @@ func (m *Model) updateRepoTable() {
 			lang = "-"
 		}
 		rows = append(rows, table.Row{
-			repo.Name,
+			sanitizeDisplay(repo.Name),
+			formatNumber(int(popularityScore(repo))),
 			formatNumber(repo.Stars),
 			formatNumber(repo.Forks),
 			lang,
-			repo.UpdatedAt.Format("2006-01-02"),
+			formatDate(repo.UpdatedAt),
 		})
 	}
 
@@ -485,56 +1835,97 @@ func (m *Model) updateRepoTable() {
 		table.WithColumns(columns),
 		table.WithRows(rows),
 		table.WithFocused(true),
-		table.WithHeight(m.height-8),
+		table.WithHeight(m.tableHeight()),
 	)
 
 	s := table.DefaultStyles()
 	s.Header = s.Header.
 		BorderStyle(lipgloss.NormalBorder()).
-		BorderForeground(lipgloss.Color("240")).
+		BorderForeground(uiRule).
 		BorderBottom(true).
 		Bold(false)
 	s.Selected = s.Selected.
-		Foreground(lipgloss.Color("229")).
-		Background(lipgloss.Color("57")).
+		Foreground(uiAccentFg).
+		Background(uiAccentBg).
 		Bold(false)
 	m.table.SetStyles(s)
+	m.table.SetCursor(cursor)
+}
+
+// headerHeight is the number of lines taken by renderHeader
+func (m Model) headerHeight() int {
+	if m.compactHeader {
+		return 1
+	}
+	return 4 // Header takes 3-4 lines
 }
 
+// tableHeight leaves room for the header, help and table borders
+func (m Model) tableHeight() int {
+	return m.height - m.headerHeight() - 4
+}
+
+// listItemLines is the height of a list item: title, description and
+// the blank line before the next one
+const listItemLines = 3
+
+// listChromeLines are the lines of the list around its items: title,
+// pagination and help
+const listChromeLines = 5
+
+// resize fits the list, table and viewport to the window size
+func (m *Model) resize() {
+	helpHeight := 3 // Help takes 2-3 lines
+	padding := 4    // Left/right padding
+	availableHeight := m.height - m.headerHeight() - helpHeight - 2
+
+	// with --items the list keeps the same page size whatever the
+	// terminal, unless it doesn't fit
+	listHeight := availableHeight
+	if m.cfg.ListItems > 0 {
+		listHeight = min(availableHeight, listChromeLines+m.cfg.ListItems*listItemLines)
+	}
+	m.list.SetSize(m.width-padding, listHeight)
+
+	// Update table
+	m.updateTableSize()
+
+	// Update viewport with proper sizing
+	m.viewport.Width = m.width - padding
+	m.viewport.Height = availableHeight
+}
+
+// updateTableSize fits the table to the window in place, keeping the
+// cursor and scroll position
 func (m *Model) updateTableSize() {
 	if m.height > 0 {
-		// Recreate table with new height
-		columns := m.table.Columns()
-		rows := m.table.Rows()
-		m.table = table.New(
-			table.WithColumns(columns),
-			table.WithRows(rows),
-			table.WithFocused(true),
-			table.WithHeight(m.height-8),
-		)
-
-		s := table.DefaultStyles()
-		s.Header = s.Header.
-			BorderStyle(lipgloss.NormalBorder()).
-			BorderForeground(lipgloss.Color("240")).
-			BorderBottom(true).
-			Bold(false)
-		s.Selected = s.Selected.
-			Foreground(lipgloss.Color("229")).
-			Background(lipgloss.Color("57")).
-			Bold(false)
-		m.table.SetStyles(s)
+		m.table.SetHeight(m.tableHeight())
 	}
 }
 
+// updateStatsView renders the stats into the viewport. The aggregation
+// is only redone here, not on every frame.
 func (m *Model) updateStatsView() {
 	content := m.renderDetailedStats()
 	m.viewport.SetContent(content)
+	m.statsStale = false
+}
+
+// invalidateStats is called when the data behind the stats changes. They
+// are rendered right away when shown, otherwise on the next visit.
+func (m *Model) invalidateStats() {
+	m.statsStale = true
+	if m.currentView == statsView {
+		m.updateStatsView()
+	}
 }
 
 func (m Model) View() string {
-	if !m.ready && m.loading {
-		return m.renderLoadingView()
+	if m.askUser {
+		return m.renderUsernamePrompt()
+	}
+	if m.askToken {
+		return m.renderTokenPrompt()
 	}
 
 	var content string
@@ -542,35 +1933,53 @@ func (m Model) View() string {
 	// Header
 	header := m.renderHeader()
 
-	// Notification bar
+	// Notification bar, the running action replaces it until its result
 	var notifBar string
-	if m.notification != "" {
+	if m.working != "" {
+		notifBar = lipgloss.NewStyle().
+			Width(m.width).
+			Align(lipgloss.Center).
+			Padding(0, 1).
+			Foreground(uiMuted).
+			Render(m.spinner.View() + " " + m.working + "...")
+	} else if m.notification != "" {
 		notifStyle := lipgloss.NewStyle().
 			Width(m.width).
 			Align(lipgloss.Center).
 			Padding(0, 1)
 
 		if m.notifSuccess {
-			notifBar = notifStyle.Background(lipgloss.Color("28")).
-				Foreground(lipgloss.Color("15")).
+			notifBar = notifStyle.Background(uiSuccessBg).
+				Foreground(uiNotifFg).
 				Render(m.notification)
 		} else {
-			notifBar = notifStyle.Background(lipgloss.Color("124")).
-				Foreground(lipgloss.Color("15")).
+			notifBar = notifStyle.Background(uiErrorBg).
+				Foreground(uiNotifFg).
 				Render(m.notification)
 		}
 	}
 
+	// Failed sections stay flagged until retried
+	failBanner := m.renderFailBanner()
+
 	// Main content based on current view with proper centering
 	switch m.currentView {
 	case repoListView:
 		content = m.renderRepoListView()
+		if m.comparing {
+			content = m.renderComparisonView()
+		}
 	case repoTableView:
 		content = m.renderRepoTableView()
 	case statsView:
 		content = m.renderStatsView()
 	case activityView:
 		content = m.renderActivityView()
+	case subscriptionsView:
+		content = m.renderSubscriptionsView()
+	}
+	if m.detail != nil {
+		content = m.renderRepoDetailView()
 	}
 
 	// Center the main content
@@ -585,6 +1994,9 @@ func (m Model) View() string {
 	if m.searchMode {
 		searchBar = m.renderSearchBar()
 	}
+	if m.exportMode {
+		searchBar = m.renderExportBar()
+	}
 
 	// Help
 	helpView := m.help.View(keys)
@@ -594,6 +2006,9 @@ func (m Model) View() string {
 	if notifBar != "" {
 		sections = append(sections, notifBar)
 	}
+	if failBanner != "" {
+		sections = append(sections, failBanner)
+	}
 	if searchBar != "" {
 		sections = append(sections, searchBar)
 	}
@@ -602,27 +2017,81 @@ func (m Model) View() string {
 	return lipgloss.JoinVertical(lipgloss.Left, sections...)
 }
 
-func (m Model) renderLoadingView() string {
-	content := fmt.Sprintf("\n%s Loading GitHub data for %s...\n\n", m.spinner.View(), m.username)
+// renderPlaceholder stands in for a view whose data is still loading
+// along with the overall progress of the load
+func (m Model) renderPlaceholder(what string) string {
+	sources := m.loadingSources()
+	done := 0
+	var checklist []string
+	for _, source := range sources {
+		mark := "○"
+		if source.loaded {
+			mark = "✓"
+			done++
+		}
+		checklist = append(checklist, mark+" "+source.name)
+	}
 
-	if m.reposLoaded {
-		content += "Repositories loaded\n"
-	} else {
-		content += "Loading repositories...\n"
+	return lipgloss.NewStyle().
+		Foreground(uiHelpDesc).
+		Padding(2).
+		Render(fmt.Sprintf("%s Loading %s for %s...\n\n%d/%d loaded: %s",
+			m.spinner.View(), what, m.username, done, len(sources), strings.Join(checklist, "  ")))
+}
+
+// renderFailBanner names the sections that failed to load, empty when
+// everything loaded
+func (m Model) renderFailBanner() string {
+	var failed []string
+	if m.reposErr != nil {
+		failed = append(failed, "repositories")
+	}
+	if m.eventsErr != nil {
+		failed = append(failed, "activity")
+	}
+	if len(failed) == 0 {
+		return ""
 	}
 
-	if m.eventsLoaded {
-		content += "Activity loaded\n"
-	} else {
-		content += "Loading activity...\n"
+	text := fmt.Sprintf("⚠ %s failed — press %s to retry", strings.Join(failed, " and "), keys.Retry.Help().Key)
+	return lipgloss.NewStyle().
+		Width(m.width).
+		Align(lipgloss.Center).
+		Foreground(nvimYellow).
+		Render(text)
+}
+
+func (m Model) renderUsernamePrompt() string {
+	content := fmt.Sprintf("\nUser '%s' was not found on GitHub.\n\n", m.username)
+	content += "Enter another username:\n\n"
+	content += m.userInput.View() + "\n\n"
+	if m.userInputErr != "" {
+		content += lipgloss.NewStyle().Background(uiErrorBg).Foreground(uiNotifFg).Padding(0, 1).Render(m.userInputErr) + "\n\n"
 	}
+	content += lipgloss.NewStyle().Foreground(uiHelpDesc).Render("enter to load • esc to quit")
+
+	return lipgloss.NewStyle().
+		Align(lipgloss.Center).
+		Width(m.width).
+		Height(m.height).
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(uiFrame).
+		Padding(2).
+		Render(content)
+}
+
+func (m Model) renderTokenPrompt() string {
+	content := "\nThe GitHub API rate limit for requests without a token is exhausted.\n\n"
+	content += "Paste a personal access token to continue, it is only kept for this session:\n\n"
+	content += m.tokenInput.View() + "\n\n"
+	content += lipgloss.NewStyle().Foreground(uiHelpDesc).Render("enter to retry • esc to continue without")
 
 	return lipgloss.NewStyle().
 		Align(lipgloss.Center).
 		Width(m.width).
 		Height(m.height).
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("62")).
+		BorderForeground(uiFrame).
 		Padding(2).
 		Render(content)
 }
@@ -634,12 +2103,16 @@ func (m Model) renderHeader() string {
 	if len(m.publicRepos) > 0 {
 		totalStars := 0
 		totalForks := 0
+		forkedRepos := 0
 		for _, repo := range m.publicRepos {
 			totalStars += repo.Stars
 			totalForks += repo.Forks
+			if repo.Fork {
+				forkedRepos++
+			}
 		}
-		stats = fmt.Sprintf("® %d repos • ⋆ %s stars • ⑂ %s forks",
-			len(m.publicRepos), formatNumber(totalStars), formatNumber(totalForks))
+		stats = fmt.Sprintf("® %d sources, %d forked repos • ⋆ %s stars • ⑂ %s forks received",
+			len(m.publicRepos)-forkedRepos, forkedRepos, formatNumber(totalStars), formatNumber(totalForks))
 	}
 
 	var viewIndicator string
@@ -652,32 +2125,164 @@ func (m Model) renderHeader() string {
 		viewIndicator = "Statistics"
 	case activityView:
 		viewIndicator = "Activity"
+	case subscriptionsView:
+		viewIndicator = "Watching"
+	}
+
+	if m.interval > 0 {
+		viewIndicator += fmt.Sprintf(" • refresh in %s", max(time.Until(m.nextRefresh), 0).Round(time.Second))
+	}
+	if m.viewFlash {
+		viewIndicator = lipgloss.NewStyle().Reverse(true).Bold(true).Render(" " + viewIndicator + " ")
 	}
 
 	headerStyle := lipgloss.NewStyle().
-		Background(lipgloss.Color("57")).
-		Foreground(lipgloss.Color("230")).
+		Background(uiAccentBg).
+		Foreground(uiAccentFg).
 		Padding(0, 2).
 		Width(m.width).
 		Align(lipgloss.Center)
 
+	if m.compactHeader {
+		return headerStyle.Render(fmt.Sprintf("%s • %s", m.username, viewIndicator))
+	}
 	headerContent := fmt.Sprintf("%s\n%s\n%s", title, stats, viewIndicator)
-	return headerStyle.Render(headerContent)
+	if !m.initials {
+		return headerStyle.Render(headerContent)
+	}
+	avatar := renderInitialsAvatar(m.username)
+	return lipgloss.JoinHorizontal(lipgloss.Top,
+		avatar, headerStyle.Width(m.width-lipgloss.Width(avatar)).Render(headerContent))
+}
+
+// renderInitialsAvatar is the placeholder of users without a profile
+// picture: the first letter of login on a block as tall as the header,
+// colored from the login so each user keeps the same one
+func renderInitialsAvatar(login string) string {
+	initial := "?"
+	if r := []rune(login); len(r) > 0 {
+		initial = strings.ToUpper(string(r[0]))
+	}
+
+	var hash uint32
+	for _, r := range strings.ToLower(login) {
+		hash = hash*31 + uint32(r)
+	}
+
+	return lipgloss.NewStyle().
+		Background(uiAvatar[hash%uint32(len(uiAvatar))]).
+		Foreground(uiNotifFg).
+		Bold(true).
+		Width(5).
+		Height(3).
+		Align(lipgloss.Center, lipgloss.Center).
+		Render(initial)
+}
+
+func (m Model) renderSubscriptionsView() string {
+	if !m.subsLoaded {
+		return m.renderPlaceholder("watched repositories")
+	}
+	return m.list.View()
+}
+
+// renderComparisonView shows the marked repos side by side, in the
+// order they were marked
+func (m Model) renderComparisonView() string {
+	var repos []PublicRepo
+	for _, fullName := range m.marked {
+		for _, repo := range m.publicRepos {
+			if repo.FullName == fullName {
+				repos = append(repos, repo)
+			}
+		}
+	}
+	if len(repos) < 2 {
+		return m.renderRepoListView()
+	}
+	return lipgloss.NewStyle().Padding(1, 2).Render(
+		renderRepoComparison(repos[0], repos[1], time.Now()) + "\n" + helpTextStyle.Render("press any key to go back"))
+}
+
+// detailPadding is the horizontal padding around the repo details
+const detailPadding = 2
+
+// renderRepoDetailView shows the repo picked with enter, its description
+// wrapped to the viewport rather than cut like in the list
+func (m Model) renderRepoDetailView() string {
+	width := m.viewport.Width - 2*detailPadding
+	return lipgloss.NewStyle().Padding(1, detailPadding).Render(
+		renderRepoDetail(*m.detail, width) + "\n" + helpTextStyle.Render("press any key to go back"))
+}
+
+// renderRepoDetail lists everything known about repo, long values
+// wrapped to width
+func renderRepoDetail(repo PublicRepo, width int) string {
+	var content strings.Builder
+	content.WriteString(titleStyle.Render(sanitizeDisplay(repo.FullName)) + "\n\n")
+	if desc := sanitizeDisplay(repo.Description); desc != "" {
+		content.WriteString(wrapText(desc, width) + "\n\n")
+	}
+
+	line := func(label, value string) {
+		content.WriteString(statLabelStyle.Render(label+": ") + value + "\n")
+	}
+	line("Stars", formatNumber(repo.Stars))
+	line("Forks", formatNumber(repo.Forks))
+	if repo.Language != "" {
+		line("Language", repo.Language)
+	}
+	if len(repo.Topics) > 0 {
+		line("Topics", wrapText(strings.Join(repo.Topics, ", "), max(width-len("Topics: "), 1)))
+	}
+	health, _ := repoHealth(repo)
+	line("Health", fmt.Sprintf("%s (%d open issues and PRs)", health, repo.OpenIssues))
+	line("Created", formatDate(repo.CreatedAt))
+	line("Updated", formatDate(repo.UpdatedAt))
+	line("URL", repo.URL)
+	if homepage := strings.TrimSpace(repo.Homepage); homepage != "" {
+		line("Homepage", homepage)
+	}
+	return content.String()
 }
 
 func (m Model) renderRepoListView() string {
+	if !m.reposLoaded {
+		return m.renderPlaceholder("repositories")
+	}
 	return m.list.View()
 }
 
 func (m Model) renderRepoTableView() string {
+	if !m.reposLoaded {
+		return m.renderPlaceholder("repositories")
+	}
 	return m.table.View()
 }
 
 func (m Model) renderStatsView() string {
-	return m.viewport.View()
+	switch {
+	case !m.reposLoaded && !m.eventsLoaded:
+		return m.renderPlaceholder("repositories and activity")
+	case !m.reposLoaded:
+		return m.renderPlaceholder("repositories")
+	case !m.eventsLoaded:
+		return m.renderPlaceholder("activity")
+	}
+	if !m.aggregating {
+		return m.viewport.View()
+	}
+
+	total := len(m.langRepos)
+	bar := m.progress.ViewAs(float64(m.langDone) / float64(total))
+	status := fmt.Sprintf("%s %d/%d repos analyzed (esc to cancel)", bar, m.langDone, total)
+	return lipgloss.JoinVertical(lipgloss.Left, status, m.viewport.View())
 }
 
 func (m Model) renderActivityView() string {
+	if !m.eventsLoaded {
+		return m.renderPlaceholder("activity")
+	}
 	return m.list.View()
 }
 
@@ -686,16 +2291,32 @@ func (m Model) renderSearchBar() string {
 		Width(m.width).
 		Align(lipgloss.Center).
 		Padding(0, 1).
-		Background(lipgloss.Color("235"))
+		Background(uiSearchBg)
 
 	searchContent := lipgloss.NewStyle().
-		Foreground(lipgloss.Color("241")).
+		Foreground(uiMuted).
 		Render("Search: ") + m.search.View()
 
 	return searchStyle.Render(searchContent)
 }
 
+func (m Model) renderExportBar() string {
+	return lipgloss.NewStyle().
+		Width(m.width).
+		Align(lipgloss.Center).
+		Padding(0, 1).
+		Background(uiSearchBg).
+		Render(lipgloss.NewStyle().Foreground(uiMuted).Render("Export to: ") + m.exportInput.View())
+}
+
 func (m Model) renderDetailedStats() string {
+	return m.renderDetailedStatsAt(time.Now())
+}
+
+// renderDetailedStatsAt renders the stats as of now, which the parts
+// depending on the current date (dormant languages, repos by year and
+// the heatmap) are measured from
+func (m Model) renderDetailedStatsAt(now time.Time) string {
 	var content strings.Builder
 
 	content.WriteString(titleStyle.Render("Detailed Statistics"))
@@ -735,16 +2356,48 @@ func (m Model) renderDetailedStats() string {
 		// Languages
 		if len(languageCount) > 0 {
 			content.WriteString("Programming Languages:\n")
-			for lang, count := range languageCount {
-				content.WriteString(fmt.Sprintf("   %s: %d repositories\n", lang, count))
+			for _, lang := range sortedByCount(languageCount) {
+				content.WriteString(fmt.Sprintf("   %s: %d repositories\n", lang, languageCount[lang]))
+			}
+			// only worth showing when the user has moved on from something
+			if active, dormant := languageActivity(m.publicRepos, now); len(dormant) > 0 {
+				if len(active) == 0 {
+					active = []string{"none"}
+				}
+				content.WriteString(fmt.Sprintf("   Active: %s / Dormant: %s\n",
+					strings.Join(active, ", "), strings.Join(dormant, ", ")))
 			}
 			content.WriteString("\n")
 		}
+
+		if timeline := renderReposByYear(reposByYear(m.publicRepos), now); timeline != "" {
+			content.WriteString(timeline)
+			content.WriteString("\n")
+		}
+	}
+
+	// Languages by code size, from the per-repo aggregation
+	if len(m.langBytes) > 0 {
+		totalBytes := 0
+		for _, bytes := range m.langBytes {
+			totalBytes += bytes
+		}
+		langs := sortedByCount(m.langBytes)
+
+		if m.langDone < len(m.langRepos) {
+			content.WriteString(fmt.Sprintf("Languages by Code Size (partial, %d/%d repos):\n", m.langDone, len(m.langRepos)))
+		} else {
+			content.WriteString("Languages by Code Size:\n")
+		}
+		for _, lang := range langs {
+			content.WriteString(fmt.Sprintf("   %s: %.1f%%\n", lang, float64(m.langBytes[lang])*100/float64(totalBytes)))
+		}
+		content.WriteString("\n")
 	}
 
 	// Activity Statistics
 	if len(m.events) > 0 {
-		content.WriteString("Activity Statistics:\n")
+		content.WriteString(fmt.Sprintf("Activity Statistics (%s):\n", m.activityScope()))
 		content.WriteString(fmt.Sprintf("   Push Events: %d\n", m.stats.PushEvents))
 		content.WriteString(fmt.Sprintf("   Pull Request Events: %d\n", m.stats.PullRequestEvents))
 		content.WriteString(fmt.Sprintf("   Issue Events: %d\n", m.stats.IssueEvents))
@@ -752,94 +2405,385 @@ func (m Model) renderDetailedStats() string {
 		content.WriteString(fmt.Sprintf("   Watch Events: %d\n", m.stats.WatchEvents))
 		content.WriteString(fmt.Sprintf("   Total Events: %d\n", m.stats.TotalEvents))
 		content.WriteString(fmt.Sprintf("   Activity Grade: %s\n", getGrade(m.stats)))
+		writeGradeBreakdown(&content, m.stats, "      ")
+		content.WriteString(m.renderOpenCounts())
+
+		content.WriteString("\n")
+		content.WriteString(renderWeekdayChart(activityByWeekday(m.events, m.loc)))
+		if heatmap := renderHeatmapAt(m.events, m.loc, now); heatmap != "" {
+			content.WriteString("\n")
+			content.WriteString(heatmap)
+		}
+
+		// where the user spends time, which can be other people's repos
+		if busiest := getTopRepos(m.events); len(busiest) > 0 {
+			content.WriteString("\n")
+			content.WriteString("Busiest Repositories:\n")
+			for i, repo := range busiest {
+				if i >= 5 {
+					break
+				}
+				content.WriteString(fmt.Sprintf("   %d. %s - %d events, last %s\n", i+1, repo.Name, repo.Count, formatDate(repo.LastActivity)))
+			}
+		}
+
+		// the feed only goes back 90 days (300 events), older
+		// contributions are missing
+		if contributed := contributedRepos(m.events, m.username, m.publicRepos); len(contributed) > 0 {
+			content.WriteString("\n")
+			content.WriteString("Contributed To (not owned, recent activity only):\n")
+			for i, repo := range contributed {
+				if i >= 5 {
+					break
+				}
+				content.WriteString(fmt.Sprintf("   %d. %s - %d pushes/PRs, last %s\n", i+1, repo.Name, repo.Count, formatDate(repo.LastActivity)))
+			}
+		}
+
+		if releases := recentReleases(m.events); len(releases) > 0 {
+			content.WriteString("\n")
+			content.WriteString("Recent Releases:\n")
+			for i, event := range releases {
+				if i >= 5 {
+					break
+				}
+				content.WriteString(fmt.Sprintf("   %d. %s %s - %s\n", i+1, event.Repo.Name, event.Payload.Release.TagName, formatDate(event.CreatedAt)))
+			}
+		}
+
+		// the events feed only holds stars given by the user
+		if starred := recentlyStarred(m.events); len(starred) > 0 {
+			content.WriteString("\n")
+			content.WriteString(fmt.Sprintf("Recently Starred by %s:\n", m.username))
+			for i, repo := range starred {
+				if i >= 5 {
+					break
+				}
+				content.WriteString(fmt.Sprintf("   %d. %s - %s\n", i+1, repo.Name, formatDate(repo.LastActivity)))
+			}
+		}
+	}
+
+	if len(m.publicRepos) > 0 {
+		if related := m.renderRelated(); related != "" {
+			content.WriteString("\n")
+			content.WriteString(related)
+		}
 	}
 
 	return content.String()
 }
 
-// Action commands
-func (m Model) cloneRepo(repo PublicRepo) tea.Cmd {
-	return func() tea.Msg {
-		cloneCmd := fmt.Sprintf("git clone %s", repo.CloneURL)
-		if err := copyToClipboard(cloneCmd); err != nil {
-			return NotificationMsg{
-				message:   fmt.Sprintf("❌ Copy Error: %v", err),
-				isSuccess: false,
-			}
+// renderRelated suggests repos sharing the user's most common topics
+func (m Model) renderRelated() string {
+	if m.client.Token == "" {
+		return "You Might Like:\n   Set GITHUB_TOKEN to get suggestions from the user's topics\n"
+	}
+	if !m.relatedLoaded {
+		return ""
+	}
+	if len(m.related.topics) == 0 {
+		return "You Might Like:\n   No suggestions, the repositories have no topics\n"
+	}
+
+	var content strings.Builder
+	content.WriteString(fmt.Sprintf("You Might Like (topics: %s):\n", strings.Join(m.related.topics, ", ")))
+	if m.related.err != nil {
+		content.WriteString(fmt.Sprintf("   unavailable (%v)\n", m.related.err))
+	}
+	for i, repo := range m.related.repos {
+		if i >= 5 {
+			break
+		}
+		content.WriteString(fmt.Sprintf("   %d. %s - ⋆ %s\n", i+1, repo.FullName, formatNumber(repo.Stars)))
+	}
+	return content.String()
+}
+
+// renderOpenCounts shows the open PRs and issues authored by the user,
+// empty until they are loaded
+func (m Model) renderOpenCounts() string {
+	if !m.openCountsLoaded {
+		return ""
+	}
+	if m.openCounts.err != nil {
+		return fmt.Sprintf("   Open PRs/Issues: unavailable (%v)\n", m.openCounts.err)
+	}
+	return fmt.Sprintf("   Open PRs: %s • Open Issues: %s\n",
+		formatNumber(m.openCounts.prs), formatNumber(m.openCounts.issues))
+}
+
+// renderWeekdayChart draws the events per weekday as horizontal bars
+func renderWeekdayChart(days [7]int) string {
+	const barWidth = 20
+
+	busiest := 0
+	for day, count := range days {
+		if count > days[busiest] {
+			busiest = day
 		}
-		return NotificationMsg{
-			message:   fmt.Sprintf("Clone command copied: %s", repo.Name),
-			isSuccess: true,
+	}
+	if days[busiest] == 0 {
+		return ""
+	}
+
+	var chart strings.Builder
+	chart.WriteString(fmt.Sprintf("Most active: %ss\n", time.Weekday(busiest)))
+	for day, count := range days {
+		filled := count * barWidth / days[busiest]
+		chart.WriteString(fmt.Sprintf("   %s %s%s %d\n",
+			time.Weekday(day).String()[:3],
+			strings.Repeat("█", filled),
+			strings.Repeat("░", barWidth-filled),
+			count))
+	}
+	return chart.String()
+}
+
+// renderReposByYear draws the repos created per year as horizontal bars,
+// from the first year to the current one. Years without a repo show as
+// zero so gaps stand out, and the current year is marked as partial.
+func renderReposByYear(years map[int]int, now time.Time) string {
+	const barWidth = 20
+
+	if len(years) == 0 {
+		return ""
+	}
+	first, last, most := now.Year(), now.Year(), 0
+	for year, count := range years {
+		first = min(first, year)
+		last = max(last, year)
+		most = max(most, count)
+	}
+
+	var chart strings.Builder
+	chart.WriteString("Repositories Created per Year:\n")
+	for year := first; year <= last; year++ {
+		filled := years[year] * barWidth / most
+		line := fmt.Sprintf("   %d %s%s %d", year,
+			strings.Repeat("█", filled),
+			strings.Repeat("░", barWidth-filled),
+			years[year])
+		if year == now.Year() {
+			line += " (so far)"
+		}
+		chart.WriteString(line + "\n")
+	}
+	return chart.String()
+}
+
+// heatmapWeeks is the number of weeks shown by renderHeatmapAt
+const heatmapWeeks = 8
+
+// renderHeatmapAt draws a contribution calendar of the weeks up to now,
+// one column per week (Sunday on top) and darker to brighter green by
+// number of events, using the day they happened on in loc
+func renderHeatmapAt(events []GitHubEvent, loc *time.Location, now time.Time) string {
+	now = now.In(loc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc)
+	start := today.AddDate(0, 0, -int(today.Weekday())-7*(heatmapWeeks-1))
+
+	var counts [heatmapWeeks][7]int
+	busiest := 0
+	for _, event := range events {
+		t := event.CreatedAt.In(loc)
+		day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+		if day.Before(start) || day.After(today) {
+			continue
+		}
+		// calendar days, so DST changes don't shift the index
+		offset := int(day.Sub(start).Hours()+12) / 24
+		counts[offset/7][offset%7]++
+		busiest = max(busiest, counts[offset/7][offset%7])
+	}
+	if busiest == 0 {
+		return ""
+	}
+
+	var grid strings.Builder
+	grid.WriteString(fmt.Sprintf("Last %d Weeks:\n", heatmapWeeks))
+	for weekday := 0; weekday < 7; weekday++ {
+		grid.WriteString("   " + time.Weekday(weekday).String()[:3] + " ")
+		for week := 0; week < heatmapWeeks; week++ {
+			// days of the current week that haven't happened yet
+			if start.AddDate(0, 0, week*7+weekday).After(today) {
+				grid.WriteString("  ")
+				continue
+			}
+			count := counts[week][weekday]
+			level := 0
+			if count > 0 {
+				level = min((count*(len(uiHeat)-1)+busiest-1)/busiest, len(uiHeat)-1)
+			}
+			grid.WriteString(lipgloss.NewStyle().Foreground(uiHeat[level]).Render("■") + " ")
 		}
+		grid.WriteString("\n")
+	}
+	return grid.String()
+}
+
+// activityScope describes which events the activity endpoint returned
+func (m Model) activityScope() string {
+	switch {
+	case m.cfg.PublicOnly:
+		return "public events only"
+	case m.client.Token != "":
+		return "includes private events if the token belongs to " + m.username
+	default:
+		return "public events"
+	}
+}
+
+// notify builds every notification, so the --no-emoji mode applies to
+// all of them
+func notify(message string, success bool) NotificationMsg {
+	if noEmoji {
+		message = stripEmoji(message)
+	}
+	return NotificationMsg{message: message, isSuccess: success}
+}
+
+// setNotification shows a notification that stays until replaced
+func (m *Model) setNotification(message string, success bool) {
+	n := notify(message, success)
+	m.notification, m.notifSuccess = n.message, n.isSuccess
+}
+
+// Action commands
+func notifyCmd(message string, success bool) tea.Cmd {
+	return func() tea.Msg {
+		return notify(message, success)
+	}
+}
+
+// actionStartedMsg tells an action is running, until its notification
+type actionStartedMsg struct {
+	label string
+}
+
+// startAction runs cmd, an action ending with a notification, after
+// showing label with the spinner. Opening a browser or copying can take
+// a moment without any other feedback.
+func startAction(label string, cmd tea.Cmd) tea.Cmd {
+	return tea.Sequence(func() tea.Msg { return actionStartedMsg{label: label} }, cmd)
+}
+
+func (m Model) cloneRepo(repo PublicRepo) tea.Cmd {
+	cloneCmd := fmt.Sprintf("git clone %s", repo.CloneURL)
+	message := fmt.Sprintf("Clone command copied: %s", repo.Name)
+	if repo.DefaultBranch != "" {
+		message += fmt.Sprintf(" (checks out %s)", repo.DefaultBranch)
 	}
+	return copyText(cloneCmd, message)
 }
 
 func (m Model) copyURL(repo PublicRepo) tea.Cmd {
+	return copyText(repo.URL, fmt.Sprintf("URL copied: %s", repo.Name))
+}
+
+// copyText copies text to the clipboard and reports success with message.
+// Without a clipboard tool the text is saved to a file and shown instead.
+func copyText(text, message string) tea.Cmd {
 	return func() tea.Msg {
-		if err := copyToClipboard(repo.URL); err != nil {
-			return NotificationMsg{
-				message:   fmt.Sprintf("❌ Copy Error: %v", err),
-				isSuccess: false,
+		err := copyToClipboard(text)
+		if errors.Is(err, errNoClipboard) {
+			if path, ferr := saveClipboardFallback(text); ferr == nil {
+				return notify(fmt.Sprintf("No clipboard tool, saved to %s: %s", path, text), true)
 			}
 		}
-		return NotificationMsg{
-			message:   fmt.Sprintf("URL copied: %s", repo.Name),
-			isSuccess: true,
+		if err != nil {
+			return notify(fmt.Sprintf("❌ Copy Error: %v", err), false)
 		}
+		return notify(message, true)
+	}
+}
+
+// copyStatsJSON copies the aggregates of the stats view as a JSON object
+func (m Model) copyStatsJSON() tea.Cmd {
+	data, err := json.MarshalIndent(buildStatsSummary(m.username, m.publicRepos, m.stats), "", "  ")
+	if err != nil {
+		return notifyCmd(fmt.Sprintf("❌ Error encoding stats: %v", err), false)
+	}
+	return copyText(string(data), fmt.Sprintf("Stats of %s copied as JSON", m.username))
+}
+
+func (m Model) copyMarkdownLink(repo PublicRepo) tea.Cmd {
+	link := fmt.Sprintf("[%s](%s)", repo.Name, repo.URL)
+	return copyText(link, fmt.Sprintf("Markdown link copied: %s", repo.Name))
+}
+
+// copyStarsBadge copies the markdown of a shields.io stars badge linking
+// to the repository
+func (m Model) copyStarsBadge(repo PublicRepo) tea.Cmd {
+	badge := fmt.Sprintf("[![stars](https://img.shields.io/github/stars/%s)](%s)", repo.FullName, repo.URL)
+	return copyText(badge, fmt.Sprintf("Stars badge copied: %s", repo.Name))
+}
+
+// openHomepage opens the website of the repository, which is not always set
+func (m Model) openHomepage(repo PublicRepo) tea.Cmd {
+	homepage := strings.TrimSpace(repo.Homepage)
+	if homepage == "" {
+		return notifyCmd(fmt.Sprintf("%s has no homepage", repo.Name), false)
 	}
+	// homepages are sometimes saved without a scheme
+	if !strings.Contains(homepage, "://") {
+		homepage = "https://" + homepage
+	}
+	return openURL(homepage, homepage)
 }
 
 func (m Model) openInBrowser(repo PublicRepo) tea.Cmd {
+	return openURL(repo.URL, repo.Name)
+}
+
+// openURL opens url in the default browser, naming it label in the notification
+func openURL(url, label string) tea.Cmd {
 	return func() tea.Msg {
 		var cmd *exec.Cmd
 
 		switch runtime.GOOS {
 		case "darwin":
-			cmd = exec.Command("open", repo.URL)
+			cmd = exec.Command("open", url)
 		case "linux":
-			cmd = exec.Command("xdg-open", repo.URL)
+			cmd = exec.Command("xdg-open", url)
 		case "windows":
-			cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", repo.URL)
+			cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
 		default:
-			return NotificationMsg{
-				message:   "❌ OS not supported for opening browser",
-				isSuccess: false,
-			}
+			return notify("❌ OS not supported for opening browser", false)
 		}
 
 		if err := cmd.Run(); err != nil {
-			return NotificationMsg{
-				message:   fmt.Sprintf("❌ Error opening browser: %v", err),
-				isSuccess: false,
-			}
+			return notify(fmt.Sprintf("❌ Error opening browser: %v", err), false)
 		}
 
-		return NotificationMsg{
-			message:   fmt.Sprintf("Opened in browser: %s", repo.Name),
-			isSuccess: true,
-		}
+		return notify(fmt.Sprintf("Opened in browser: %s", label), true)
 	}
 }
 
 // Initialize new model with bubbles components
-func NewModel(username string) Model {
+func NewModel(client *github.Client, username string, cfg Config) Model {
 	// List component with better styling
 	delegate := list.NewDefaultDelegate()
 	delegate.Styles.SelectedTitle = delegate.Styles.SelectedTitle.
-		Background(lipgloss.Color("57")).
-		Foreground(lipgloss.Color("230")).
+		Background(uiAccentBg).
+		Foreground(uiAccentFg).
 		Padding(0, 1)
 	delegate.Styles.SelectedDesc = delegate.Styles.SelectedDesc.
-		Background(lipgloss.Color("57")).
-		Foreground(lipgloss.Color("254")).
+		Background(uiAccentBg).
+		Foreground(uiAccentDesc).
 		Padding(0, 1)
 
-	l := list.New([]list.Item{}, delegate, 0, 0)
+	l := list.New([]list.Item{}, itemDelegate{delegate}, 0, 0)
 	l.SetShowStatusBar(false)
-	l.SetFilteringEnabled(false)
+	// the search bar replaces the list filter unless native_filter is set
+	l.SetFilteringEnabled(cfg.NativeFilter)
+	// fixed pages tell their number rather than a dot each
+	if cfg.ListItems > 0 {
+		l.Paginator.Type = paginator.Arabic
+	}
 	l.Title = "Loading repositories..."
 	l.Styles.Title = lipgloss.NewStyle().
-		Foreground(lipgloss.Color("86")).
+		Foreground(uiListTitle).
 		Bold(true).
 		Padding(0, 2)
 
@@ -850,37 +2794,80 @@ func NewModel(username string) Model {
 	v := viewport.New(0, 0)
 	v.Style = lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("62")).
+		BorderForeground(uiFrame).
 		Padding(1, 2)
 
 	// Help component
 	h := help.New()
-	h.Styles.ShortKey = lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
-	h.Styles.ShortDesc = lipgloss.NewStyle().Foreground(lipgloss.Color("243"))
-	h.Styles.ShortSeparator = lipgloss.NewStyle().Foreground(lipgloss.Color("237"))
+	h.Styles.ShortKey = lipgloss.NewStyle().Foreground(uiMuted)
+	h.Styles.ShortDesc = lipgloss.NewStyle().Foreground(uiHelpDesc)
+	h.Styles.ShortSeparator = lipgloss.NewStyle().Foreground(uiHelpSep)
 
 	// Spinner component
 	s := spinner.New()
 	s.Spinner = spinner.Dot
-	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
+	s.Style = lipgloss.NewStyle().Foreground(uiSpinner)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	loc, _ := cfg.location()
+	notifDuration, _ := cfg.notifDuration()
+	interval, _ := cfg.interval()
+
+	// Progress bar for language aggregation
+	p := progress.New(progress.WithDefaultGradient())
 
 	// Search input
 	ti := textinput.New()
-	ti.Placeholder = "Search repositories by name or description..."
+	ti.Placeholder = "Search by name, description or topic (lang:go stars:>100 fork:false)..."
 	ti.CharLimit = 100
 	ti.Width = 50
 
+	// Username input, used when the user doesn't exist. Longer than a
+	// username so a pasted profile URL isn't cut before it is normalized.
+	ui := textinput.New()
+	ui.Placeholder = "GitHub username"
+	ui.CharLimit = 100
+	ui.Width = 39
+
+	// Export file name, the extension picks the format
+	ei := textinput.New()
+	ei.Placeholder = "file.json or file.csv"
+	ei.CharLimit = 255
+	ei.Width = 50
+
+	// Token input, masked as it is typed or pasted
+	tk := textinput.New()
+	tk.Placeholder = "ghp_..."
+	tk.EchoMode = textinput.EchoPassword
+	tk.EchoCharacter = '•'
+	tk.CharLimit = 255
+	tk.Width = 50
+
 	return Model{
-		username:     username,
-		list:         l,
-		table:        t,
-		viewport:     v,
-		help:         h,
-		spinner:      s,
-		search:       ti,
-		currentView:  repoListView,
-		loading:      true,
-		reposLoaded:  false,
-		eventsLoaded: false,
+		client:        client,
+		username:      username,
+		lastSeen:      lastSeen(username),
+		cfg:           cfg,
+		loc:           loc,
+		notifDuration: notifDuration,
+		interval:      interval,
+		nextRefresh:   time.Now().Add(interval),
+		list:          l,
+		table:         t,
+		viewport:      v,
+		help:          h,
+		spinner:       s,
+		progress:      p,
+		search:        ti,
+		userInput:     ui,
+		exportInput:   ei,
+		tokenInput:    tk,
+		currentView:   repoListView,
+		loading:       true,
+		statsStale:    true,
+		reposLoaded:   false,
+		eventsLoaded:  false,
+		loadCtx:       ctx,
+		cancelLoad:    cancel,
 	}
 }
@@ -0,0 +1,380 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"gitact/pkg/github"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// golden compares got with testdata/name.golden, or rewrites the file
+// with -update
+func golden(t *testing.T, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.MkdirAll("testdata", 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%v, run go test -update to create it", err)
+	}
+	if got != string(want) {
+		t.Errorf("output differs from %s, run go test -update if the change is intended\ngot:\n%s\nwant:\n%s", path, got, want)
+	}
+}
+
+// newTestModel returns a model for octocat with repos loaded, sized to a
+// 100x30 window. The cache dir is redirected so no state is read or kept.
+func newTestModel(t *testing.T, repos []PublicRepo) Model {
+	t.Helper()
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	m := NewModel(github.NewClient(""), "octocat", Config{})
+	m.width, m.height = 100, 30
+	m.publicRepos = repos
+	m.reposLoaded = true
+	m.resize()
+	return m
+}
+
+// testRepos returns n repos named repo-00, repo-01... updated a day apart
+func testRepos(n int) []PublicRepo {
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	repos := make([]PublicRepo, n)
+	for i := range repos {
+		repos[i] = PublicRepo{
+			Name:      fmt.Sprintf("repo-%02d", i),
+			FullName:  fmt.Sprintf("octocat/repo-%02d", i),
+			Stars:     i,
+			UpdatedAt: base.AddDate(0, 0, -i),
+		}
+	}
+	return repos
+}
+
+func TestUpdateTableSizeKeepsCursor(t *testing.T) {
+	m := newTestModel(t, testRepos(50))
+	m.updateRepoTable()
+	m.table.SetCursor(30)
+
+	prev := m.table.Height()
+	for _, height := range []int{40, 15, 30} {
+		grows := height > m.height
+		m.height = height
+		m.updateTableSize()
+		if got := m.table.Cursor(); got != 30 {
+			t.Errorf("height %d: cursor = %d, want 30", height, got)
+		}
+		if got := m.table.Height(); (got > prev) != grows {
+			t.Errorf("height %d: table height went from %d to %d", height, prev, got)
+		}
+		prev = m.table.Height()
+		if got := len(m.table.Rows()); got != 50 {
+			t.Errorf("height %d: %d rows, want 50", height, got)
+		}
+	}
+}
+
+func TestUpdateRepoTableColumns(t *testing.T) {
+	// updated "in the future" so the score has no decay at all
+	updated := time.Now().Add(time.Minute)
+	repos := []PublicRepo{
+		{Name: "cli", Language: "Go", Stars: 1200, Forks: 30, UpdatedAt: updated},
+		{Name: "notes", Stars: 7, UpdatedAt: updated},
+	}
+	m := newTestModel(t, repos)
+	m.updateRepoTable()
+
+	var titles []string
+	for _, c := range m.table.Columns() {
+		titles = append(titles, c.Title)
+	}
+	wantTitles := []string{"Name", "Score", "Stars", "Forks", "Language", "Updated"}
+	if !slices.Equal(titles, wantTitles) {
+		t.Fatalf("columns = %v, want %v", titles, wantTitles)
+	}
+
+	date := formatDate(updated)
+	want := [][]string{
+		// score is stars + 2 * forks without decay
+		{"cli", "1.3k", "1.2k", "30", "Go", date},
+		{"notes", "7", "7", "0", "-", date},
+	}
+	rows := m.table.Rows()
+	if len(rows) != len(want) {
+		t.Fatalf("%d rows, want %d", len(rows), len(want))
+	}
+	for i, row := range rows {
+		if !slices.Equal(row, want[i]) {
+			t.Errorf("row %d = %v, want %v", i, row, want[i])
+		}
+	}
+}
+
+func TestRenderDetailedStatsGolden(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	day := func(n int) time.Time { return now.AddDate(0, 0, -n) }
+
+	repos := []PublicRepo{
+		{Name: "cli", FullName: "octocat/cli", Language: "Go", Stars: 1520, Forks: 88, CreatedAt: day(900), UpdatedAt: day(2)},
+		{Name: "site", FullName: "octocat/site", Language: "TypeScript", Stars: 230, Forks: 12, CreatedAt: day(500), UpdatedAt: day(40)},
+		{Name: "old-lib", FullName: "octocat/old-lib", Language: "Perl", Stars: 41, Forks: 3, CreatedAt: day(2000), UpdatedAt: day(1500)},
+		{Name: "notes", FullName: "octocat/notes", Stars: 2, CreatedAt: day(30), UpdatedAt: day(30)},
+		{Name: "fork", FullName: "octocat/fork", Language: "Go", Fork: true, CreatedAt: day(100), UpdatedAt: day(100)},
+	}
+	release := GitHubEvent{Type: "ReleaseEvent", Repo: Repo{Name: "octocat/cli"}, CreatedAt: day(3).Add(-2 * time.Hour)}
+	release.Payload.Release = &github.Release{TagName: "v2.1.0"}
+	events := []GitHubEvent{
+		event("PushEvent", "octocat/cli", day(0).Add(-time.Hour)),
+		event("PushEvent", "octocat/cli", day(1)),
+		event("PullRequestEvent", "golang/go", day(1).Add(-3*time.Hour)),
+		event("WatchEvent", "charmbracelet/bubbletea", day(2)),
+		release,
+		event("IssuesEvent", "octocat/site", day(5)),
+		event("CreateEvent", "octocat/notes", day(30)),
+		event("PushEvent", "golang/go", day(31)),
+	}
+
+	m := newTestModel(t, repos)
+	m.loc = time.UTC
+	m.events = events
+	m.eventsLoaded = true
+	m.stats = calculateStats(events)
+
+	got := terminalEscape.ReplaceAllString(m.renderDetailedStatsAt(now), "")
+	golden(t, "detailed_stats", got)
+}
+
+func TestUsernameInputTakesProfileURL(t *testing.T) {
+	m := newTestModel(t, nil)
+	url := "https://github.com/" + strings.Repeat("a", 39) + "/"
+	m.userInput.SetValue(url)
+	if got := m.userInput.Value(); got != url {
+		t.Errorf("input kept %q, want the whole URL", got)
+	}
+}
+
+func TestHeaderInitialsAvatar(t *testing.T) {
+	m := newTestModel(t, testRepos(3))
+	// the initial sits in the middle of a 5x3 block left of the header
+	middleLine := func() string {
+		lines := strings.Split(terminalEscape.ReplaceAllString(m.renderHeader(), ""), "\n")
+		return lines[1]
+	}
+
+	if got := middleLine(); strings.HasPrefix(got, "  O  ") {
+		t.Errorf("initials drawn for a user with a picture: %q", got)
+	}
+	m.initials = true
+	if got := middleLine(); !strings.HasPrefix(got, "  O  ") {
+		t.Errorf("no initials for a user without a picture: %q", got)
+	}
+}
+
+func TestWatchRefreshKeepsState(t *testing.T) {
+	m := newTestModel(t, testRepos(10))
+	m.updateRepoTable()
+	m.table.SetCursor(4)
+	m.eventsLoaded = true
+	m.loading = false
+	m.aggregating = true
+	m.interval = time.Minute
+	m.nextRefresh = time.Now().Add(-time.Second)
+
+	// the refetch is only started, the requests are not run here
+	next, cmd := m.Update(watchTickMsg{})
+	m = next.(Model)
+	if cmd == nil {
+		t.Fatal("no refresh started")
+	}
+	if !m.reposLoaded || !m.eventsLoaded || m.loading || !m.aggregating {
+		t.Fatalf("state reset by the refresh: reposLoaded %v, eventsLoaded %v, loading %v, aggregating %v",
+			m.reposLoaded, m.eventsLoaded, m.loading, m.aggregating)
+	}
+	if len(m.publicRepos) != 10 {
+		t.Fatalf("the current repos were dropped before the new ones arrived")
+	}
+
+	// the new data replaces the old in place
+	repos := testRepos(12)
+	next, _ = m.Update(reposLoadedMsg{repos: repos})
+	m = next.(Model)
+	if len(m.publicRepos) != 12 || len(m.table.Rows()) != 12 {
+		t.Errorf("%d repos, %d rows after the refresh, want 12", len(m.publicRepos), len(m.table.Rows()))
+	}
+	if got := m.table.Cursor(); got != 4 {
+		t.Errorf("table cursor = %d after the refresh, want 4", got)
+	}
+	if !m.aggregating {
+		t.Error("the language aggregation was stopped")
+	}
+}
+
+func TestRefreshCancelsPreviousLoad(t *testing.T) {
+	m := newTestModel(t, testRepos(3))
+	m.refresh()
+	first := m.loadCtx
+	if first.Err() != nil {
+		t.Fatal("the context of the refresh is already cancelled")
+	}
+
+	m.refresh()
+	if first.Err() == nil {
+		t.Error("a second refresh left the requests of the first running")
+	}
+	if m.loadCtx.Err() != nil {
+		t.Error("the context of the second refresh is cancelled")
+	}
+}
+
+func TestJumpToRepo(t *testing.T) {
+	repos := testRepos(6)
+	for i := range repos {
+		repos[i].Language = "Go"
+	}
+	repos[1].Language = "Rust"
+	repos[3].Language = "Rust"
+
+	m := newTestModel(t, repos)
+	m.dominantLang = "Go"
+	m.langOnly = true
+	m.currentView = activityView
+
+	selected := func() string {
+		item, ok := m.list.SelectedItem().(repoItem)
+		if !ok {
+			return ""
+		}
+		return item.repo.FullName
+	}
+
+	// the Go list doesn't hold repo-01 and repo-03, indexes differ from publicRepos
+	m.jumpToRepo("octocat/repo-04")
+	if got := selected(); got != "octocat/repo-04" || m.currentView != repoListView {
+		t.Errorf("selected %q in view %v, want octocat/repo-04 in the repo list", got, m.currentView)
+	}
+	if !m.langOnly {
+		t.Error("the language filter was dropped for a repo it shows")
+	}
+
+	m.jumpToRepo("OctoCat/Repo-03")
+	if got := selected(); got != "octocat/repo-03" {
+		t.Errorf("selected %q, want octocat/repo-03", got)
+	}
+	if m.langOnly {
+		t.Error("the language filter still hides the repo jumped to")
+	}
+
+	if cmd := m.jumpToRepo("someone/else"); cmd == nil {
+		t.Error("no notification for a repo of someone else")
+	}
+}
+
+func TestRenderReposByYear(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	got := renderReposByYear(map[int]int{2021: 2, 2023: 1}, now)
+	want := "Repositories Created per Year:\n" +
+		"   2021 ████████████████████ 2\n" +
+		"   2022 ░░░░░░░░░░░░░░░░░░░░ 0\n" +
+		"   2023 ██████████░░░░░░░░░░ 1\n" +
+		"   2024 ░░░░░░░░░░░░░░░░░░░░ 0 (so far)\n"
+	if got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+
+	if got := renderReposByYear(nil, now); got != "" {
+		t.Errorf("no repos: got %q", got)
+	}
+}
+
+func TestTokenPromptSwapsClient(t *testing.T) {
+	m := newTestModel(t, nil)
+	old := m.client
+	m.promptToken()
+	m.tokenInput.SetValue(" Bearer ghp_test ")
+
+	next, _ := m.handleTokenInput(tea.KeyMsg{Type: tea.KeyEnter})
+	m = *next.(*Model)
+	if m.client == old {
+		t.Fatal("the token was set on the shared client")
+	}
+	if old.Token != "" {
+		t.Errorf("old client token = %q, requests in flight would see it change", old.Token)
+	}
+	if m.client.Token != "ghp_test" || m.askToken {
+		t.Errorf("token %q, askToken %v after entering it", m.client.Token, m.askToken)
+	}
+}
+
+func TestCycleEventTypes(t *testing.T) {
+	m := newTestModel(t, nil)
+	m.cfg.EventTypes = "push,pr"
+
+	if got := m.activityTypes(); !slices.Equal(got, []string{"push", "pr"}) {
+		t.Fatalf("before cycling: %v, want the --event-types list", got)
+	}
+	// one step per type, then back to the configured list
+	for _, name := range eventTypeOrder {
+		m.cycleEventTypes()
+		if got := m.activityTypes(); !slices.Equal(got, []string{name}) {
+			t.Errorf("got %v, want [%s]", got, name)
+		}
+	}
+	m.cycleEventTypes()
+	if got := m.activityTypes(); !slices.Equal(got, []string{"push", "pr"}) {
+		t.Errorf("after a full cycle: %v, want the --event-types list", got)
+	}
+
+	// an invalid list was reported at startup, every event is kept
+	m.cfg.EventTypes = "nope"
+	if got := m.activityTypes(); got != nil {
+		t.Errorf("invalid list: %v, want nil", got)
+	}
+}
+
+func TestRepoDetailWrapsDescription(t *testing.T) {
+	repos := testRepos(3)
+	repos[0].Description = strings.Repeat("a long description that goes on ", 10) + "END"
+	m := newTestModel(t, repos)
+	m.updateRepoList()
+
+	next, _ := m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	m = next.(Model)
+	if m.detail == nil || m.detail.FullName != "octocat/repo-00" {
+		t.Fatalf("enter showed %v, want the details of octocat/repo-00", m.detail)
+	}
+
+	view := terminalEscape.ReplaceAllString(m.renderRepoDetailView(), "")
+	for line := range strings.SplitSeq(view, "\n") {
+		if w := lipgloss.Width(line); w > m.viewport.Width {
+			t.Errorf("line %q is %d wide, the viewport %d", line, w, m.viewport.Width)
+		}
+	}
+	if !strings.Contains(view, "END") {
+		t.Error("the end of the description was cut instead of wrapped")
+	}
+
+	next, _ = m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	if m = next.(Model); m.detail != nil {
+		t.Error("a key didn't close the details")
+	}
+}
@@ -1,21 +1,246 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"math"
 	"os"
 	"os/exec"
+	"regexp"
 	"runtime"
+	"strconv"
 	"strings"
+	"time"
+	"unicode"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/term"
 )
 
-// format
+// formatNumber abbreviates large counts ("1.2k", "3.4M"). It runs for every
+// row on each render, so it avoids fmt and formats into a stack buffer.
+// Rounding happens before picking the unit, so 999999 gives "1.0M".
 func formatNumber(n int) string {
-	if n >= 1000000 {
-		return fmt.Sprintf("%.1fM", float64(n)/1000000)
-	} else if n >= 1000 {
-		return fmt.Sprintf("%.1fk", float64(n)/1000)
+	if n < 1000 {
+		return strconv.Itoa(n)
+	}
+
+	tenths, suffix := (n+50)/100, byte('k')
+	if tenths >= 10000 {
+		tenths, suffix = (n+50000)/100000, 'M'
+	}
+
+	var buf [24]byte
+	b := strconv.AppendInt(buf[:0], int64(tenths/10), 10)
+	b = append(b, '.', byte('0'+tenths%10), suffix)
+	return string(b)
+}
+
+// parseFormattedNumber reads back a count written by formatNumber ("1.5k",
+// "2M") or a plain integer
+func parseFormattedNumber(s string) (int, error) {
+	s = strings.TrimSpace(s)
+	multiplier := 1.0
+	switch {
+	case strings.HasSuffix(s, "k"), strings.HasSuffix(s, "K"):
+		multiplier = 1e3
+	case strings.HasSuffix(s, "M"):
+		multiplier = 1e6
+	}
+	if multiplier == 1 {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid number %q", s)
+		}
+		return n, nil
+	}
+
+	// only digits and a decimal point, ParseFloat alone accepts "1e3" or "Inf"
+	digits := s[:len(s)-1]
+	if digits == "" || strings.Trim(digits, "0123456789.") != "" {
+		return 0, fmt.Errorf("invalid number %q", s)
+	}
+	f, err := strconv.ParseFloat(digits, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q", s)
+	}
+	return int(math.Round(f * multiplier)), nil
+}
+
+// parseSince parses an --updated-since value into the earliest time it
+// allows: a date ("2024-01-01"), a number of days, weeks, months or years
+// ("30d", "2w", "6mo", "1y") or a Go duration ("36h")
+func parseSince(s string, now time.Time) (time.Time, error) {
+	s = strings.TrimSpace(s)
+	if t, err := time.ParseInLocation("2006-01-02", s, time.Local); err == nil {
+		return t, nil
+	}
+
+	units := []struct {
+		suffix              string
+		years, months, days int
+	}{
+		{"mo", 0, 1, 0}, {"d", 0, 0, 1}, {"w", 0, 0, 7}, {"y", 1, 0, 0},
+	}
+	for _, u := range units {
+		if n, err := strconv.Atoi(strings.TrimSuffix(s, u.suffix)); err == nil && strings.HasSuffix(s, u.suffix) {
+			if n <= 0 {
+				break
+			}
+			return now.AddDate(-n*u.years, -n*u.months, -n*u.days), nil
+		}
+	}
+
+	if d, err := time.ParseDuration(s); err == nil && d > 0 {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q, use a date like 2024-01-01 or a window like 30d, 2w, 6mo, 1y", s)
+}
+
+// relativeDate is the layout value selecting relative dates ("3d ago")
+const relativeDate = "relative"
+
+// dateLayout is set from the config at startup and used by formatDate
+var dateLayout = "2006-01-02"
+
+// noEmoji is set from the config at startup, notify drops emoji then
+var noEmoji bool
+
+// terminalEscape matches the escape sequences a terminal would act on:
+// CSI (colors, cursor moves), OSC (title, hyperlinks) and two-byte ones
+var terminalEscape = regexp.MustCompile(`\x1b(\[[0-?]*[ -/]*[@-~]|\][^\x07\x1b]*(\x07|\x1b\\)?|[0-~])`)
+
+// sanitizeDisplay makes text from the API safe to print: escape sequences,
+// control characters and bidirectional overrides are removed, invalid
+// UTF-8 is replaced and whitespace is collapsed to single spaces
+func sanitizeDisplay(s string) string {
+	s = strings.ToValidUTF8(s, "\uFFFD")
+	s = terminalEscape.ReplaceAllString(s, "")
+
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '\n', r == '\t', r == '\r':
+			b.WriteRune(' ')
+		case unicode.IsControl(r),
+			r >= 0x202A && r <= 0x202E,            // embeddings and overrides
+			r >= 0x2066 && r <= 0x2069,            // isolates
+			r == 0x200E, r == 0x200F, r == 0x061C: // direction marks
+			continue
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// stripEmoji removes emoji and pictographs from s, for terminals, logs and
+// screen readers that handle them badly
+func stripEmoji(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r >= 0x1F000 && r <= 0x1FAFF, // pictographs, emoticons, symbols
+			r >= 0x2600 && r <= 0x27BF, // misc symbols and dingbats (❌)
+			r == 0xFE0F, r == 0x200D:   // variation selector and joiner
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// formatDate formats t with the configured date format. Every date shown
+// to the user goes through here.
+func formatDate(t time.Time) string {
+	if dateLayout != relativeDate {
+		return t.Format(dateLayout)
+	}
+
+	d := time.Since(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	case d < 30*24*time.Hour:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	case d < 365*24*time.Hour:
+		return fmt.Sprintf("%dmo ago", int(d.Hours()/(24*30)))
+	default:
+		return fmt.Sprintf("%dy ago", int(d.Hours()/(24*365)))
+	}
+}
+
+// formatDateTime is formatDate with the time of day, for events.
+// Relative dates are precise enough on their own.
+func formatDateTime(t time.Time) string {
+	if dateLayout == relativeDate {
+		return formatDate(t)
+	}
+	return formatDate(t) + t.Format(" 15:04")
+}
+
+// popularityHalfLife is how long after its last update a repository's
+// popularity score halves
+const popularityHalfLife = 180 * 24 * time.Hour
+
+// popularityScore ranks repositories by traction rather than raw stars:
+//
+//	(stars + 2*forks) * 0.5^(time since update / popularityHalfLife)
+//
+// Forks count double as they take more commitment than a star, and the
+// decay favors repositories that are still maintained.
+func popularityScore(repo PublicRepo) float64 {
+	return popularityScoreAt(repo, time.Now())
+}
+
+func popularityScoreAt(repo PublicRepo, now time.Time) float64 {
+	base := float64(repo.Stars + 2*repo.Forks)
+	age := now.Sub(repo.UpdatedAt)
+	if age < 0 {
+		age = 0
+	}
+	return base * math.Pow(0.5, float64(age)/float64(popularityHalfLife))
+}
+
+// Repository health thresholds. The issue ratio is open issues and PRs
+// per 100 stars, only judged from healthMinStars stars on since a couple
+// of issues on a tiny project says little.
+const (
+	healthStaleAfter     = 365 * 24 * time.Hour
+	healthNeglectedAfter = 2 * 365 * 24 * time.Hour
+	healthMinStars       = 20
+	healthStaleRatio     = 10
+	healthNeglectedRatio = 25
+)
+
+// repoHealth gives a maintenance signal for a repository:
+//
+//	neglected  not updated for 2 years, or over 25 open issues per 100 stars
+//	stale      not updated for a year, or over 10 open issues per 100 stars
+//	healthy    otherwise
+func repoHealth(repo PublicRepo) (label string, color lipgloss.Color) {
+	return repoHealthAt(repo, time.Now())
+}
+
+func repoHealthAt(repo PublicRepo, now time.Time) (string, lipgloss.Color) {
+	age := now.Sub(repo.UpdatedAt)
+	ratio := 0
+	if repo.Stars >= healthMinStars {
+		ratio = repo.OpenIssues * 100 / repo.Stars
+	}
+
+	switch {
+	case age > healthNeglectedAfter || ratio > healthNeglectedRatio:
+		return "neglected", nvimRed
+	case age > healthStaleAfter || ratio > healthStaleRatio:
+		return "stale", nvimYellow
+	default:
+		return "healthy", nvimGreen
 	}
-	return fmt.Sprintf("%d", n)
 }
 
 // score
@@ -23,11 +248,7 @@ func getGrade(stats GitHubStats) string {
 	if stats.TotalEvents == 0 {
 		return "F"
 	}
-	score := float64(stats.PushEvents)*1.0 +
-		float64(stats.PullRequestEvents)*3.0 +
-		float64(stats.CreateEvents)*1.0 +
-		float64(stats.IssueEvents)*1.5 +
-		float64(stats.WatchEvents)*0.5
+	score := gradeScore(stats)
 
 	switch {
 	case score >= 100:
@@ -51,26 +272,283 @@ func getGrade(stats GitHubStats) string {
 	}
 }
 
-func formatEventShort(event GitHubEvent) string {
+// gradeComponents are the keys of gradeBreakdown, in display order, with
+// their label in the stats view
+var gradeComponents = []struct{ key, label string }{
+	{"pull_request_events", "Pull Request Events"},
+	{"push_events", "Push Events"},
+	{"issue_events", "Issue Events"},
+	{"create_events", "Create Events"},
+	{"watch_events", "Watch Events"},
+}
+
+// gradeBreakdown returns the points each event type adds to the score
+// behind getGrade, weighted by how much effort it takes
+func gradeBreakdown(stats GitHubStats) map[string]float64 {
+	return map[string]float64{
+		"push_events":         float64(stats.PushEvents) * 1.0,
+		"pull_request_events": float64(stats.PullRequestEvents) * 3.0,
+		"create_events":       float64(stats.CreateEvents) * 1.0,
+		"issue_events":        float64(stats.IssueEvents) * 1.5,
+		"watch_events":        float64(stats.WatchEvents) * 0.5,
+	}
+}
+
+// gradeScore sums gradeBreakdown
+func gradeScore(stats GitHubStats) float64 {
+	score := 0.0
+	for _, points := range gradeBreakdown(stats) {
+		score += points
+	}
+	return score
+}
+
+// formatPoints prints a score without a useless ".0"
+func formatPoints(points float64) string {
+	return strconv.FormatFloat(points, 'f', -1, 64)
+}
+
+// formatEventShort describes an event in a few words. The actor is named
+// when it isn't selfLogin, as in feeds mixing several users.
+func formatEventShort(event GitHubEvent, selfLogin string) string {
+	return withActor(eventSummary(event), event, selfLogin)
+}
+
+// formatCollapsedShort is formatEventShort for an item of collapseEvents
+func formatCollapsedShort(c collapsedEvent, selfLogin string) string {
+	if c.count < 2 {
+		return formatEventShort(c.event, selfLogin)
+	}
+	return withActor(fmt.Sprintf("%d pushes to %s", c.count, c.event.Repo.Name), c.event, selfLogin)
+}
+
+// withActor prefixes desc with the actor of event when it isn't selfLogin
+func withActor(desc string, event GitHubEvent, selfLogin string) string {
+	if actor := event.Actor.Login; actor != "" && !strings.EqualFold(actor, selfLogin) {
+		return actor + ": " + desc
+	}
+	return desc
+}
+
+// eventSummary describes an event in a few words. Types without a case
+// fall back to their name without the Event suffix, e.g. a GollumEvent
+// gives "Gollum in owner/repo".
+func eventSummary(event GitHubEvent) string {
+	// a few events, e.g. on deleted repos, come without a name
+	repo := event.Repo.Name
+	if repo == "" {
+		repo = "an unknown repository"
+	}
+
 	switch event.Type {
 	case "PushEvent":
-		return fmt.Sprintf("Pushed to %s", event.Repo.Name)
+		return fmt.Sprintf("Pushed to %s", repo)
 	case "IssuesEvent":
-		return fmt.Sprintf("Issue in %s", event.Repo.Name)
+		if verb := eventAction(event); verb != "" {
+			return fmt.Sprintf("%s issue in %s", verb, repo)
+		}
+		return fmt.Sprintf("Issue in %s", repo)
 	case "WatchEvent":
-		return fmt.Sprintf("Starred %s", event.Repo.Name)
+		return fmt.Sprintf("Starred %s", repo)
 	case "ForkEvent":
-		return fmt.Sprintf("Forked %s", event.Repo.Name)
+		return fmt.Sprintf("Forked %s", repo)
 	case "CreateEvent":
-		return fmt.Sprintf("Created %s", event.Repo.Name)
+		// ref is empty when the repository itself was created
+		switch event.Payload.RefType {
+		case "branch", "tag":
+			if event.Payload.Ref != "" {
+				return fmt.Sprintf("Created %s %s in %s", event.Payload.RefType, event.Payload.Ref, repo)
+			}
+		}
+		return fmt.Sprintf("Created %s", repo)
+	case "ReleaseEvent":
+		if release := event.Payload.Release; release != nil && release.TagName != "" {
+			return fmt.Sprintf("Released %s in %s", release.TagName, repo)
+		}
+		return fmt.Sprintf("Release in %s", repo)
 	case "PullRequestEvent":
-		return fmt.Sprintf("PR in %s", event.Repo.Name)
+		if verb := eventAction(event); verb != "" {
+			return fmt.Sprintf("%s PR in %s", verb, repo)
+		}
+		return fmt.Sprintf("PR in %s", repo)
 	default:
 		return fmt.Sprintf("%s in %s",
-			strings.TrimSuffix(event.Type, "Event"), event.Repo.Name)
+			strings.TrimSuffix(event.Type, "Event"), repo)
+	}
+}
+
+// eventAction returns the capitalized action of an issue or pull request
+// event ("Opened", "Merged"...), empty for other actions. Merged pull
+// requests are reported by GitHub as closed with merged set.
+func eventAction(event GitHubEvent) string {
+	switch event.Payload.Action {
+	case "opened":
+		return "Opened"
+	case "reopened":
+		return "Reopened"
+	case "closed":
+		if pr := event.Payload.PullRequest; pr != nil && pr.Merged {
+			return "Merged"
+		}
+		return "Closed"
+	}
+	return ""
+}
+
+// wrapText word-wraps s to width display columns, keeping its existing
+// line breaks. Words longer than a line are split. Wide runes (CJK,
+// emoji) count for their display width.
+func wrapText(s string, width int) string {
+	if width <= 0 {
+		return s
+	}
+
+	var lines []string
+	for _, paragraph := range strings.Split(s, "\n") {
+		var line strings.Builder
+		lineWidth := 0
+		flush := func() {
+			lines = append(lines, line.String())
+			line.Reset()
+			lineWidth = 0
+		}
+
+		for _, word := range strings.Fields(paragraph) {
+			for lipgloss.Width(word) > width {
+				if lineWidth > 0 {
+					flush()
+				}
+				var head string
+				head, word = splitAtWidth(word, width)
+				lines = append(lines, head)
+			}
+			w := lipgloss.Width(word)
+			if w == 0 {
+				continue
+			}
+			if lineWidth > 0 && lineWidth+1+w > width {
+				flush()
+			}
+			if lineWidth > 0 {
+				line.WriteByte(' ')
+				lineWidth++
+			}
+			line.WriteString(word)
+			lineWidth += w
+		}
+		flush()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// truncate shortens s to width display columns, ending it with an
+// ellipsis when something was cut. A width of 0 or less leaves s alone.
+func truncate(s string, width int) string {
+	if width <= 0 || lipgloss.Width(s) <= width {
+		return s
+	}
+	if width == 1 {
+		return "…"
 	}
+	head, _ := splitAtWidth(s, width-1)
+	return head + "…"
 }
 
+// terminalWidth returns the width of the terminal on stdout, 0 when it
+// isn't a terminal and output shouldn't be cut
+func terminalWidth() int {
+	width, _, err := term.GetSize(os.Stdout.Fd())
+	if err != nil {
+		return 0
+	}
+	return width
+}
+
+// isTerminal reports whether f is a terminal rather than a pipe or a
+// file, e.g. to fall back to the plain dashboard when stdout is redirected
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// splitAtWidth cuts s after at most width display columns, always keeping
+// at least one rune so callers make progress
+func splitAtWidth(s string, width int) (string, string) {
+	used := 0
+	for i, r := range s {
+		w := lipgloss.Width(string(r))
+		if used+w > width && i > 0 {
+			return s[:i], s[i:]
+		}
+		used += w
+	}
+	return s, ""
+}
+
+// repoWebURL returns the web page of an event's repository. Repo.URL is the
+// API URL (https://api.github.com/repos/owner/name), which GitHub keeps
+// pointing at renamed or transferred repos, so it is preferred over the
+// name recorded at event time.
+func repoWebURL(repo Repo) string {
+	const apiPrefix = "https://api.github.com/repos/"
+	if path, ok := strings.CutPrefix(repo.URL, apiPrefix); ok && path != "" {
+		return "https://github.com/" + path
+	}
+	return "https://github.com/" + repo.Name
+}
+
+// normalizeUsername accepts a username the way people paste it: "@octocat",
+// "github.com/octocat" or "https://github.com/octocat/" all give "octocat".
+// A repository URL gives its owner.
+func normalizeUsername(raw string) (string, error) {
+	input := strings.TrimSpace(raw)
+	if input == "" {
+		return "", fmt.Errorf("username can't be empty")
+	}
+
+	s := input
+	if _, rest, ok := strings.Cut(s, "://"); ok {
+		s = rest
+	}
+	s = strings.TrimPrefix(s, "www.")
+	if host, path, ok := strings.Cut(s, "/"); ok && strings.Contains(host, ".") {
+		if !strings.EqualFold(host, "github.com") {
+			return "", fmt.Errorf("%q is not a GitHub profile URL", input)
+		}
+		s, _, _ = strings.Cut(strings.Trim(path, "/"), "/")
+	} else if s != input {
+		// a scheme without a path, e.g. https://github.com
+		return "", fmt.Errorf("%q is not a GitHub profile URL", input)
+	}
+	s = strings.TrimPrefix(s, "@")
+
+	if !isValidUsername(s) {
+		return "", fmt.Errorf("invalid username %q: GitHub usernames are up to 39 letters, digits or hyphens, not starting or ending with a hyphen", input)
+	}
+	return s, nil
+}
+
+// isValidUsername checks the GitHub username format locally, so obviously
+// bad input doesn't cost a request: 1 to 39 ASCII letters, digits or
+// hyphens, without a hyphen at either end
+func isValidUsername(s string) bool {
+	if s == "" || len(s) > 39 || s[0] == '-' || s[len(s)-1] == '-' {
+		return false
+	}
+	for _, r := range s {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+// errNoClipboard is returned by copyToClipboard when no clipboard tool is installed
+var errNoClipboard = errors.New("no clipboard tool found (install xclip, xsel or wl-copy)")
+
 func copyToClipboard(text string) error {
 	var cmd *exec.Cmd
 
@@ -82,8 +560,10 @@ func copyToClipboard(text string) error {
 			cmd = exec.Command("xclip", "-selection", "clipboard")
 		} else if _, err := exec.LookPath("xsel"); err == nil {
 			cmd = exec.Command("xsel", "--clipboard", "--input")
+		} else if _, err := exec.LookPath("wl-copy"); err == nil {
+			cmd = exec.Command("wl-copy")
 		} else {
-			return fmt.Errorf("no utilitary to copy found")
+			return errNoClipboard
 		}
 	case "windows":
 		cmd = exec.Command("clip")
@@ -95,10 +575,32 @@ func copyToClipboard(text string) error {
 	return cmd.Run()
 }
 
+// saveClipboardFallback writes text to a new file in the temp directory for
+// systems without a clipboard tool, and returns the file path. Each copy gets
+// its own file so a predictable name can't be pre-created by another user.
+func saveClipboardFallback(text string) (string, error) {
+	f, err := os.CreateTemp("", "gitact-clipboard-*")
+	if err != nil {
+		return "", err
+	}
+	if _, err := f.WriteString(text + "\n"); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
+
 // Fonctions d'aide et d'information
 func showUsage() {
 	fmt.Fprintf(os.Stderr, "Usage: %s <username>\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "   or: %s <username> <username>...\n", os.Args[0])
 	fmt.Fprintf(os.Stderr, "   or: %s --repos <username>\n", os.Args[0])
+	fmt.Fprintf(os.Stderr, "   or: %s --compare <username> <username>\n", os.Args[0])
 	fmt.Fprintf(os.Stderr, "Example: %s octocat\n", os.Args[0])
 	fmt.Fprintf(os.Stderr, "         %s --repos octocat\n", os.Args[0])
 	fmt.Fprintf(os.Stderr, "\nuse '%s --help' for more informations.\n", os.Args[0])
@@ -111,11 +613,69 @@ func showHelp() {
 	fmt.Printf("Built with Charm's Bubbles UI components for a delightful terminal experience.\n\n")
 	fmt.Printf("Usage:\n")
 	fmt.Printf("  %s <username>        Interactive dashboard with multiple views\n", os.Args[0])
+	fmt.Printf("  %s <user> <user>...  Pick a user from a list, backspace returns to it\n", os.Args[0])
 	fmt.Printf("  %s --repos <username> Detailed repository listing\n\n", os.Args[0])
 	fmt.Printf("Options:\n")
 	fmt.Printf("  -h, --help     Show this help message\n")
 	fmt.Printf("  -v, --version  Show version information\n")
-	fmt.Printf("  --repos        Display all public repositories with detailed statistics\n\n")
+	fmt.Printf("  --repos        Display all public repositories with detailed statistics\n")
+	fmt.Printf("  --min-stars <n> With --repos, only include repositories with at least n stars\n")
+	fmt.Printf("  --updated-since <when> With --repos, only include repositories updated since\n")
+	fmt.Printf("                 a date (2024-01-01) or within a window (30d, 2w, 6mo, 1y)\n")
+	fmt.Printf("  --has-file <path> With --repos, only include repositories containing path\n")
+	fmt.Printf("                 (e.g. Dockerfile, go.mod, .github/workflows), one request each\n")
+	fmt.Printf("  --max-pages <n> Stop listing repositories after n pages of 100 (default 10),\n")
+	fmt.Printf("                 0 for no limit\n")
+	fmt.Printf("  --count        With --repos, print only the number of public repositories\n")
+	fmt.Printf("  --activity     Print the recent activity, one event per line\n")
+	fmt.Printf("  --include-commits With --activity --json, add the SHA and message of\n")
+	fmt.Printf("                 every pushed commit\n")
+	fmt.Printf("  --grade        Print the activity grade and the points each event type\n")
+	fmt.Printf("                 added to it\n")
+	fmt.Printf("  --summary      With --json, print the profile, repository totals, languages\n")
+	fmt.Printf("                 and activity grade as one document. Sections that fail to\n")
+	fmt.Printf("                 load are left out and listed under \"errors\"\n")
+	fmt.Printf("  --no-mouse     Start without mouse support, so text can be selected and\n")
+	fmt.Printf("                 copied with the terminal\n")
+	fmt.Printf("  --native-filter Fuzzy filter the repository and activity lists on / instead\n")
+	fmt.Printf("                 of the search bar (no lang:, stars: or fork:)\n")
+	fmt.Printf("  --collapse     Merge consecutive pushes to the same repository within\n")
+	fmt.Printf("                 10 minutes into one activity item\n")
+	fmt.Printf("  --accent <hex> Color of the header, titles and selection, e.g. '#7aa2f7'\n")
+	fmt.Printf("  --plain        Print a text version of the dashboard (top repositories,\n")
+	fmt.Printf("                 languages, grade, recent events) for CI logs and pipes.\n")
+	fmt.Printf("                 Used automatically when stdout is not a terminal\n")
+	fmt.Printf("  --orgs         List the organizations the user is a public member of\n")
+	fmt.Printf("  --badge        Write an SVG badge with the activity grade and total stars\n")
+	fmt.Printf("  --output <file> With --badge, write to a file instead of stdout\n")
+	fmt.Printf("  --compare      Compare the totals of two users (repos, followers, stars...)\n")
+	fmt.Printf("  --json         With --repos, print repositories as JSON. With --activity,\n")
+	fmt.Printf("                 print the events as JSON. With --grade, the score and its\n")
+	fmt.Printf("                 breakdown by event type as JSON. With --summary, everything\n")
+	fmt.Printf("                 above in one document. With --compare,\n")
+	fmt.Printf("                 print the totals and the winner of each metric as JSON\n")
+	fmt.Printf("  --csv          With --repos, print repositories as CSV\n")
+	fmt.Printf("  --format <tmpl> With --repos, print a Go template for each repository,\n")
+	fmt.Printf("                 e.g. '{{.Name}}\\t{{.Stars}}' (fields of github.Repository)\n")
+	fmt.Printf("  --fields <list> Columns for --json/--csv, in order (e.g. name,stars,language)\n")
+	fmt.Printf("                 Valid: %s\n", strings.Join(repoFieldNames, ", "))
+	fmt.Printf("  --public-only  Only show public activity. By default a token belonging to\n")
+	fmt.Printf("                 the user also returns their private events\n")
+	fmt.Printf("  --limit-activity <n> Only show the n most recent activity events. Stats\n")
+	fmt.Printf("                 still cover all fetched events unless --limit-stats is set\n")
+	fmt.Printf("  --limit-stats  Compute activity stats on the limited events only\n")
+	fmt.Printf("  --event-types <list> Only show these event types in the activity feed,\n")
+	fmt.Printf("                 e.g. push,pr. Valid: %s\n", strings.Join(eventTypeOrder, ", "))
+	fmt.Printf("  --items <n>    Show at most n items per page in the lists, the rest on\n")
+	fmt.Printf("                 the next pages (default: fit the terminal)\n")
+	fmt.Printf("  --date-format <f> iso (default), us, relative, or a Go layout (\"02 Jan 2006\")\n")
+	fmt.Printf("  --notif-duration <d> How long notifications stay visible (default 3s)\n")
+	fmt.Printf("  --interval <d> Refresh the dashboard every d (e.g. 5m, at least 10s), with\n")
+	fmt.Printf("                 a countdown in the header\n")
+	fmt.Printf("  --no-emoji     Leave emoji out of notifications\n")
+	fmt.Printf("  --no-rate-check Skip the rate limit request made before the dashboard starts\n")
+	fmt.Printf("  --proxy <url>  Send API requests through a proxy (default: $GITACT_PROXY,\n")
+	fmt.Printf("                 then HTTP_PROXY/HTTPS_PROXY; NO_PROXY is always honoured)\n\n")
 	fmt.Printf("GitHub Token (Recommended):\n")
 	fmt.Printf("  Set GITHUB_TOKEN environment variable to avoid rate limits:\n")
 	fmt.Printf("  • Without token: 60 requests/hour\n")
@@ -127,19 +687,47 @@ func showHelp() {
 	fmt.Printf(" Repository List  - Browse repos with search functionality\n")
 	fmt.Printf(" Table View       - Detailed tabular data (stars, forks, language)\n")
 	fmt.Printf(" Statistics       - Comprehensive stats and insights\n")
-	fmt.Printf("  Activity Feed    - Recent GitHub activity timeline\n\n")
+	fmt.Printf("  Activity Feed    - Recent GitHub activity timeline\n")
+	fmt.Printf(" Watching         - Repositories the user is watching\n\n")
 	fmt.Printf("Navigation:\n")
 	fmt.Printf("  ↑/↓ or j/k    Navigate items\n")
 	fmt.Printf("  ←/→ or h/l    Switch between views\n")
 	fmt.Printf("  tab           Next view\n")
 	fmt.Printf("  /             Search repositories (in list view)\n")
-	fmt.Printf("  enter         Select item\n")
+	fmt.Printf("  enter         Select item, show the repository details (list and table views)\n")
 	fmt.Printf("  c             Copy git clone command\n")
-	fmt.Printf("  x             Copy repository URL\n")
-	fmt.Printf("  o             Open repository in browser\n")
+	fmt.Printf("  x             Copy repository URL (list and activity views)\n")
+	fmt.Printf("  o             Open repository in browser (list and activity views),\n")
+	fmt.Printf("                or the release page of a release event\n")
+	fmt.Printf("  g             Go to the event's repo in the list (activity view)\n")
+	fmt.Printf("  m             Copy Markdown link (list and table views)\n")
+	fmt.Printf("  a             Analyze languages across all repos (in stats view, esc to stop)\n")
+	fmt.Printf("  s             Sort repositories by stars or popularity (list and table views)\n")
+	fmt.Printf("  J             Copy the statistics as JSON (stats view)\n")
+	fmt.Printf("  t             Show one event type at a time (activity view)\n")
+	fmt.Printf("  L             Only list repositories in the most common language (toggle)\n")
+	fmt.Printf("  space         Mark the selected repository to compare (list view)\n")
+	fmt.Printf("  v             Compare the two marked repositories side by side\n")
+	fmt.Printf("  w             Open the homepage of the selected repository\n")
+	fmt.Printf("  b             Copy a shields.io stars badge (markdown) of the selected repository\n")
+	fmt.Printf("  f             Show the funding links of the selected repository\n")
+	fmt.Printf("  e             Export the current view to a file (.csv for CSV, JSON otherwise)\n")
 	fmt.Printf("  r             Refresh all data\n")
+	fmt.Printf("  R             Retry only the sections that failed to load\n")
 	fmt.Printf("  ?             Toggle help\n")
+	fmt.Printf("  H             Collapse the header to one line\n")
 	fmt.Printf("  q/esc         Quit\n\n")
+	path, err := configPath()
+	if err != nil {
+		path = "gitact/config.json in your config directory"
+	}
+	fmt.Printf("Configuration:\n")
+	fmt.Printf("  Settings are read from %s\n", path)
+	fmt.Printf("  Key bindings can be overridden per action, e.g.\n")
+	fmt.Printf("  {\"keys\": {\"up\": [\"up\", \"u\"], \"down\": [\"down\", \"d\"]}}\n")
+	fmt.Printf("  Actions: %s\n\n", strings.Join(keyActions, ", "))
+	fmt.Printf("Exit status:\n")
+	fmt.Printf("  0 success, 1 error, 2 user not found, 3 API rate limit exceeded\n\n")
 	fmt.Printf("Examples:\n")
 	fmt.Printf("  %s karpathy          # Explore karpathy's ML repositories\n", os.Args[0])
 	fmt.Printf("  %s --repos torvalds  # List all of torvalds' projects\n", os.Args[0])
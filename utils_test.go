@@ -0,0 +1,363 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gitact/pkg/github"
+)
+
+func TestFormatNumber(t *testing.T) {
+	tests := []struct {
+		n    int
+		want string
+	}{
+		{0, "0"},
+		{999, "999"},
+		{1000, "1.0k"},
+		{1049, "1.0k"},
+		{1050, "1.1k"},
+		{9999, "10.0k"},
+		{999949, "999.9k"},
+		{999950, "1.0M"},
+		{999999, "1.0M"},
+		{1000000, "1.0M"},
+		{12345678, "12.3M"},
+	}
+	for _, tt := range tests {
+		if got := formatNumber(tt.n); got != tt.want {
+			t.Errorf("formatNumber(%d) = %q, want %q", tt.n, got, tt.want)
+		}
+	}
+}
+
+func BenchmarkFormatNumber(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		formatNumber(i % 2000000)
+	}
+}
+
+func TestFormatEventShortActor(t *testing.T) {
+	ev := GitHubEvent{Type: "WatchEvent", Actor: github.Actor{Login: "alice"}, Repo: Repo{Name: "bob/tool"}}
+
+	tests := []struct {
+		name, self, want string
+	}{
+		{"own event", "alice", "Starred bob/tool"},
+		{"own event, other case", "Alice", "Starred bob/tool"},
+		{"someone else's event", "carol", "alice: Starred bob/tool"},
+	}
+	for _, tt := range tests {
+		if got := formatEventShort(ev, tt.self); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	// events without an actor never get a prefix
+	ev.Actor.Login = ""
+	if got := formatEventShort(ev, "carol"); got != "Starred bob/tool" {
+		t.Errorf("no actor: got %q", got)
+	}
+}
+
+func TestSaveClipboardFallback(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+
+	first, err := saveClipboardFallback("one")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := saveClipboardFallback("two")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first == second {
+		t.Fatalf("both copies went to %s", first)
+	}
+	if !strings.HasPrefix(filepath.Base(first), "gitact-clipboard-") {
+		t.Errorf("path = %s", first)
+	}
+
+	data, err := os.ReadFile(first)
+	if err != nil {
+		t.Fatalf("reported path can't be read: %v", err)
+	}
+	if string(data) != "one\n" {
+		t.Errorf("content = %q", data)
+	}
+}
+
+func TestEventSummaryCreate(t *testing.T) {
+	tests := []struct {
+		refType, ref, want string
+	}{
+		{"branch", "feature", "Created branch feature in a/b"},
+		{"tag", "v1.0.0", "Created tag v1.0.0 in a/b"},
+		{"repository", "", "Created a/b"},
+		// a branch or tag without its name reads as the repository itself
+		{"branch", "", "Created a/b"},
+		{"", "", "Created a/b"},
+	}
+	for _, tt := range tests {
+		ev := GitHubEvent{
+			Type:    "CreateEvent",
+			Repo:    Repo{Name: "a/b"},
+			Payload: github.Payload{RefType: tt.refType, Ref: tt.ref},
+		}
+		if got := eventSummary(ev); got != tt.want {
+			t.Errorf("ref_type %q, ref %q: got %q, want %q", tt.refType, tt.ref, got, tt.want)
+		}
+	}
+}
+
+func TestParseFormattedNumber(t *testing.T) {
+	tests := []struct {
+		s    string
+		want int
+	}{
+		{"0", 0},
+		{"42", 42},
+		{" 42 ", 42},
+		{"1.5k", 1500},
+		{"1.5K", 1500},
+		{"12k", 12000},
+		{"2M", 2000000},
+		{"1.2M", 1200000},
+	}
+	for _, tt := range tests {
+		got, err := parseFormattedNumber(tt.s)
+		if err != nil || got != tt.want {
+			t.Errorf("parseFormattedNumber(%q) = %d, %v, want %d", tt.s, got, err, tt.want)
+		}
+	}
+
+	for _, s := range []string{"", "k", "-5", "abc", "1.5x", "1e3k", "Infk", "1.2.3k"} {
+		if n, err := parseFormattedNumber(s); err == nil {
+			t.Errorf("parseFormattedNumber(%q) = %d, want an error", s, n)
+		}
+	}
+}
+
+func TestParseFormattedNumberRoundTrip(t *testing.T) {
+	// counts formatNumber shows exactly read back unchanged
+	for _, n := range []int{0, 999, 1500, 12300, 2000000} {
+		s := formatNumber(n)
+		got, err := parseFormattedNumber(s)
+		if err != nil || got != n {
+			t.Errorf("%d -> %q -> %d, %v", n, s, got, err)
+		}
+	}
+}
+
+func TestWrapText(t *testing.T) {
+	tests := []struct {
+		name  string
+		s     string
+		width int
+		want  string
+	}{
+		{"fits", "short line", 20, "short line"},
+		{"wraps on words", "the quick brown fox", 10, "the quick\nbrown fox"},
+		{"keeps line breaks", "first\nsecond line here", 11, "first\nsecond line\nhere"},
+		{"keeps blank lines", "a\n\nb", 5, "a\n\nb"},
+		{"splits long words", "abcdefghij", 4, "abcd\nefgh\nij"},
+		{"multibyte runes", "héllo wörld ünïcode", 11, "héllo wörld\nünïcode"},
+		{"wide runes", "日本語のテキスト", 6, "日本語\nのテキ\nスト"},
+		{"no width", "left alone", 0, "left alone"},
+	}
+	for _, tt := range tests {
+		if got := wrapText(tt.s, tt.width); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeUsername(t *testing.T) {
+	tests := []struct {
+		raw, want string
+	}{
+		{"octocat", "octocat"},
+		{"  octocat  ", "octocat"},
+		{"@octocat", "octocat"},
+		{"github.com/octocat", "octocat"},
+		{"www.github.com/octocat", "octocat"},
+		{"https://github.com/octocat", "octocat"},
+		{"https://github.com/octocat/", "octocat"},
+		{"http://GitHub.com/octocat", "octocat"},
+		// a repository URL gives its owner
+		{"https://github.com/octocat/hello-world", "octocat"},
+	}
+	for _, tt := range tests {
+		got, err := normalizeUsername(tt.raw)
+		if err != nil || got != tt.want {
+			t.Errorf("normalizeUsername(%q) = %q, %v, want %q", tt.raw, got, err, tt.want)
+		}
+	}
+
+	invalid := []string{
+		"",
+		"   ",
+		"-octocat",
+		"octo cat",
+		"octo_cat",
+		"https://github.com",
+		"https://gitlab.com/octocat",
+		"https://github.com/" + strings.Repeat("a", 40),
+	}
+	for _, raw := range invalid {
+		if got, err := normalizeUsername(raw); err == nil {
+			t.Errorf("normalizeUsername(%q) = %q, want an error", raw, got)
+		}
+	}
+}
+
+func TestIsValidUsername(t *testing.T) {
+	tests := []struct {
+		s    string
+		want bool
+	}{
+		{"octocat", true},
+		{"a", true},
+		{"Octo-Cat-42", true},
+		{strings.Repeat("a", 39), true},
+		{strings.Repeat("a", 40), false},
+		{"", false},
+		{"-octocat", false},
+		{"octocat-", false},
+		{"octo_cat", false},
+		{"octo.cat", false},
+		{"octocät", false},
+	}
+	for _, tt := range tests {
+		if got := isValidUsername(tt.s); got != tt.want {
+			t.Errorf("isValidUsername(%q) = %v, want %v", tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestRepoHealth(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	tests := []struct {
+		name       string
+		age        time.Duration
+		stars      int
+		openIssues int
+		want       string
+	}{
+		{"recent", 10 * day, 100, 0, "healthy"},
+		{"just under a year", 364 * day, 100, 0, "healthy"},
+		{"over a year", 366 * day, 100, 0, "stale"},
+		{"over two years", 731 * day, 100, 0, "neglected"},
+		{"10 issues per 100 stars", 10 * day, 100, 10, "healthy"},
+		{"11 issues per 100 stars", 10 * day, 100, 11, "stale"},
+		{"26 issues per 100 stars", 10 * day, 100, 26, "neglected"},
+		// too few stars for the ratio to mean anything
+		{"tiny project with issues", 10 * day, 19, 15, "healthy"},
+		{"ratio from 20 stars on", 10 * day, 20, 6, "neglected"},
+	}
+	for _, tt := range tests {
+		repo := PublicRepo{Stars: tt.stars, OpenIssues: tt.openIssues, UpdatedAt: now.Add(-tt.age)}
+		if got, _ := repoHealthAt(repo, now); got != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		s    string
+		want time.Time
+	}{
+		{"2024-01-01", time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local)},
+		{" 2023-12-31 ", time.Date(2023, 12, 31, 0, 0, 0, 0, time.Local)},
+		{"30d", now.AddDate(0, 0, -30)},
+		{"2w", now.AddDate(0, 0, -14)},
+		{"6mo", now.AddDate(0, -6, 0)},
+		{"1y", now.AddDate(-1, 0, 0)},
+		{"36h", now.Add(-36 * time.Hour)},
+		{"90m", now.Add(-90 * time.Minute)},
+	}
+	for _, tt := range tests {
+		got, err := parseSince(tt.s, now)
+		if err != nil || !got.Equal(tt.want) {
+			t.Errorf("parseSince(%q) = %v, %v, want %v", tt.s, got, err, tt.want)
+		}
+	}
+
+	for _, s := range []string{"", "yesterday", "0d", "-3d", "2024-13-01", "-1h", "d", "3x"} {
+		if got, err := parseSince(s, now); err == nil {
+			t.Errorf("parseSince(%q) = %v, want an error", s, got)
+		}
+	}
+}
+
+func TestEventSummary(t *testing.T) {
+	tests := []struct {
+		name    string
+		typ     string
+		repo    string
+		payload github.Payload
+		want    string
+	}{
+		{"push", "PushEvent", "a/b", github.Payload{}, "Pushed to a/b"},
+		{"issue opened", "IssuesEvent", "a/b", github.Payload{Action: "opened"}, "Opened issue in a/b"},
+		{"issue reopened", "IssuesEvent", "a/b", github.Payload{Action: "reopened"}, "Reopened issue in a/b"},
+		{"issue closed", "IssuesEvent", "a/b", github.Payload{Action: "closed"}, "Closed issue in a/b"},
+		{"issue labeled", "IssuesEvent", "a/b", github.Payload{Action: "labeled"}, "Issue in a/b"},
+		{"watch", "WatchEvent", "a/b", github.Payload{}, "Starred a/b"},
+		{"fork", "ForkEvent", "a/b", github.Payload{}, "Forked a/b"},
+		{"create repository", "CreateEvent", "a/b", github.Payload{RefType: "repository"}, "Created a/b"},
+		{"create branch", "CreateEvent", "a/b", github.Payload{RefType: "branch", Ref: "dev"}, "Created branch dev in a/b"},
+		{"release", "ReleaseEvent", "a/b", github.Payload{Release: &github.Release{TagName: "v1.2.0"}}, "Released v1.2.0 in a/b"},
+		{"release without tag", "ReleaseEvent", "a/b", github.Payload{}, "Release in a/b"},
+		{"PR opened", "PullRequestEvent", "a/b", github.Payload{Action: "opened"}, "Opened PR in a/b"},
+		{"PR merged", "PullRequestEvent", "a/b", github.Payload{Action: "closed", PullRequest: &github.PullRequest{Merged: true}}, "Merged PR in a/b"},
+		{"PR closed unmerged", "PullRequestEvent", "a/b", github.Payload{Action: "closed", PullRequest: &github.PullRequest{}}, "Closed PR in a/b"},
+		{"PR synchronized", "PullRequestEvent", "a/b", github.Payload{Action: "synchronize"}, "PR in a/b"},
+		{"other type", "GollumEvent", "a/b", github.Payload{}, "Gollum in a/b"},
+		{"no repo name", "PushEvent", "", github.Payload{}, "Pushed to an unknown repository"},
+		{"other type, no repo name", "GollumEvent", "", github.Payload{}, "Gollum in an unknown repository"},
+	}
+	for _, tt := range tests {
+		ev := GitHubEvent{Type: tt.typ, Repo: Repo{Name: tt.repo}, Payload: tt.payload}
+		if got := eventSummary(ev); got != tt.want {
+			t.Errorf("%s: eventSummary = %q, want %q", tt.name, got, tt.want)
+		}
+		// without an actor to name, the short form is the summary
+		if got := formatEventShort(ev, ""); got != tt.want {
+			t.Errorf("%s: formatEventShort = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSanitizeDisplay(t *testing.T) {
+	tests := []struct {
+		name, s, want string
+	}{
+		{"plain", "A terminal tool", "A terminal tool"},
+		{"unicode kept", "Outil CLI ✨ 日本語", "Outil CLI ✨ 日本語"},
+		{"colors", "\x1b[31mred\x1b[0m text", "red text"},
+		{"cursor moves", "clear\x1b[2J\x1b[Hscreen", "clearscreen"},
+		{"window title, BEL", "\x1b]0;pwned\x07name", "name"},
+		{"hyperlink, ST", "\x1b]8;;https://evil.example\x1b\\click\x1b]8;;\x1b\\", "click"},
+		{"two-byte escape", "a\x1bcb", "ab"},
+		{"control characters", "bell\x07 back\x08space\x00", "bell backspace"},
+		{"C1 control", "a\u009bb", "ab"},
+		{"bidi override", "user\u202egnp.exe", "usergnp.exe"},
+		{"bidi isolate and marks", "a\u2066b\u2069c\u200fd", "abcd"},
+		{"whitespace collapsed", "line one\n\tline  two\r\n", "line one line two"},
+		{"invalid UTF-8", "bad\xffbyte", "bad�byte"},
+	}
+	for _, tt := range tests {
+		if got := sanitizeDisplay(tt.s); got != tt.want {
+			t.Errorf("%s: sanitizeDisplay(%q) = %q, want %q", tt.name, tt.s, got, tt.want)
+		}
+	}
+}